@@ -12,21 +12,112 @@ var (
 	)
 	UsernameField = field.StringField(
 		"username",
-		field.WithDescription("Ping Federate account username"),
-		field.WithRequired(true),
+		field.WithDescription("Ping Federate account username, required unless OAuth is configured"),
 	)
 	PasswordField = field.StringField(
 		"password",
-		field.WithDescription("Ping Federate account password"),
-		field.WithRequired(true),
+		field.WithDescription("Ping Federate account password, required unless OAuth is configured"),
+	)
+	OAuthClientIDField = field.StringField(
+		"oauth-client-id",
+		field.WithDescription("OAuth client ID used to obtain admin API access tokens with the client credentials grant"),
+	)
+	OAuthClientSecretField = field.StringField(
+		"oauth-client-secret",
+		field.WithDescription("OAuth client secret used to obtain admin API access tokens"),
+	)
+	OAuthTokenURLField = field.StringField(
+		"oauth-token-url",
+		field.WithDescription("Token endpoint of the authorization server, ex: https://pingfederateserver.com:9031/as/token.oauth2"),
+	)
+	OAuthScopesField = field.StringSliceField(
+		"oauth-scopes",
+		field.WithDescription("Scopes to request with the admin API access token"),
+	)
+	CABundlePathField = field.StringField(
+		"ca-bundle-path",
+		field.WithDescription("Path to a PEM bundle of CA certificates trusted for the admin API, in addition to the system roots"),
+	)
+	ClientCertPathField = field.StringField(
+		"client-cert-path",
+		field.WithDescription("Path to a PEM client certificate used for mutual TLS"),
+	)
+	ClientKeyPathField = field.StringField(
+		"client-key-path",
+		field.WithDescription("Path to the PEM private key of the client certificate"),
+	)
+	ClientPKCS12PathField = field.StringField(
+		"client-pkcs12-path",
+		field.WithDescription("Path to a PKCS#12 archive holding the client certificate and private key used for mutual TLS"),
+	)
+	ClientPKCS12PasswordField = field.StringField(
+		"client-pkcs12-password",
+		field.WithDescription("Password of the PKCS#12 archive"),
+	)
+	InsecureSkipVerifyField = field.BoolField(
+		"insecure-skip-verify",
+		field.WithDescription("Skip TLS certificate verification of the admin API. Only use this for lab instances"),
+		field.WithDefaultValue(false),
+	)
+	AddPrerequisiteRolesField = field.BoolField(
+		"add-prerequisite-roles",
+		field.WithDescription("When granting a role that requires another role, such as Expression Admin requiring Admin, grant the required role as well"),
+		field.WithDefaultValue(false),
+	)
+	LockoutProtectionField = field.BoolField(
+		"lockout-protection",
+		field.WithDescription("Refuse revokes and deactivations that would remove the last active Admin or the connector's own account. Deleting those accounts is always refused"),
+		field.WithDefaultValue(true),
+	)
+	ProtectedUsernamesField = field.StringSliceField(
+		"protected-usernames",
+		field.WithDescription("Break-glass administrative accounts that the connector never modifies"),
+	)
+	ReplicateChangesField = field.BoolField(
+		"replicate-changes",
+		field.WithDescription("Replicate the configuration to the cluster engine nodes once changes made by the connector stop coming in for two seconds"),
+		field.WithDefaultValue(false),
+	)
+	ClientSecretOverlapHoursField = field.IntField(
+		"client-secret-overlap-hours",
+		field.WithDescription("Hours a rotated OAuth client secret stays valid as a secondary secret, 0 revokes it immediately"),
+		field.WithDefaultValue(24),
 	)
 
 	configurationFields = []field.SchemaField{
 		InstanceUrlField,
 		UsernameField,
 		PasswordField,
+		OAuthClientIDField,
+		OAuthClientSecretField,
+		OAuthTokenURLField,
+		OAuthScopesField,
+		CABundlePathField,
+		ClientCertPathField,
+		ClientKeyPathField,
+		ClientPKCS12PathField,
+		ClientPKCS12PasswordField,
+		InsecureSkipVerifyField,
+		AddPrerequisiteRolesField,
+		LockoutProtectionField,
+		ProtectedUsernamesField,
+		ReplicateChangesField,
+		ClientSecretOverlapHoursField,
 	}
+
+	fieldRelationships = []field.SchemaFieldRelationship{
+		field.FieldsRequiredTogether(UsernameField, PasswordField),
+		field.FieldsRequiredTogether(OAuthClientIDField, OAuthClientSecretField, OAuthTokenURLField),
+		field.FieldsAtLeastOneUsed(UsernameField, OAuthClientIDField),
+		field.FieldsMutuallyExclusive(UsernameField, OAuthClientIDField),
+		field.FieldsDependentOn([]field.SchemaField{OAuthScopesField}, []field.SchemaField{OAuthClientIDField}),
+		field.FieldsRequiredTogether(ClientCertPathField, ClientKeyPathField),
+		field.FieldsMutuallyExclusive(ClientCertPathField, ClientPKCS12PathField),
+		field.FieldsDependentOn([]field.SchemaField{ClientPKCS12PasswordField}, []field.SchemaField{ClientPKCS12PathField}),
+	}
+
 	Configuration = field.NewConfiguration(
 		configurationFields,
+		fieldRelationships...,
 	)
 )
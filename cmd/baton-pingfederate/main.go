@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/conductorone/baton-pingfed/pkg/connector"
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
 	"github.com/conductorone/baton-sdk/pkg/config"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/field"
@@ -17,6 +19,10 @@ import (
 
 var version = "dev"
 
+// connectors holds the connectors built by this process, so the changes they made are replicated to
+// the cluster before it exits.
+var connectors []*connector.Connector
+
 func main() {
 	ctx := context.Background()
 
@@ -34,6 +40,12 @@ func main() {
 	cmd.Version = version
 
 	err = cmd.Execute()
+	for _, cb := range connectors {
+		flushErr := cb.FlushReplication(ctx)
+		if flushErr != nil {
+			fmt.Fprintln(os.Stderr, flushErr.Error())
+		}
+	}
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
@@ -47,16 +59,43 @@ func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, e
 		return nil, err
 	}
 
+	var oauth *client.OAuthConfig
+	if v.GetString(OAuthClientIDField.FieldName) != "" {
+		oauth = &client.OAuthConfig{
+			ClientID:     v.GetString(OAuthClientIDField.FieldName),
+			ClientSecret: v.GetString(OAuthClientSecretField.FieldName),
+			TokenURL:     v.GetString(OAuthTokenURLField.FieldName),
+			Scopes:       v.GetStringSlice(OAuthScopesField.FieldName),
+		}
+	}
+
+	tlsConfig := &client.TLSConfig{
+		CABundlePath:       v.GetString(CABundlePathField.FieldName),
+		ClientCertPath:     v.GetString(ClientCertPathField.FieldName),
+		ClientKeyPath:      v.GetString(ClientKeyPathField.FieldName),
+		PKCS12Path:         v.GetString(ClientPKCS12PathField.FieldName),
+		PKCS12Password:     v.GetString(ClientPKCS12PasswordField.FieldName),
+		InsecureSkipVerify: v.GetBool(InsecureSkipVerifyField.FieldName),
+	}
+
 	cb, err := connector.New(
 		ctx,
 		v.GetString(InstanceUrlField.FieldName),
 		v.GetString(UsernameField.FieldName),
 		v.GetString(PasswordField.FieldName),
+		oauth,
+		tlsConfig,
+		v.GetBool(AddPrerequisiteRolesField.FieldName),
+		v.GetBool(LockoutProtectionField.FieldName),
+		v.GetStringSlice(ProtectedUsernamesField.FieldName),
+		v.GetBool(ReplicateChangesField.FieldName),
+		time.Duration(v.GetInt(ClientSecretOverlapHoursField.FieldName))*time.Hour,
 	)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
 	}
+	connectors = append(connectors, cb)
 	connector, err := connectorbuilder.NewConnector(ctx, cb)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
@@ -0,0 +1,191 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	accessTokenManagerEntitlementName = "use"
+)
+
+type accessTokenManagerBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// accessTokenManagerResource convert an AccessTokenManager into a Resource.
+func accessTokenManagerResource(manager *client.AccessTokenManager) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"id":                      manager.ID,
+		"name":                    manager.Name,
+		"pluginType":              manager.PluginDescriptorRef.ID,
+		"tokenLifetime":           manager.Configuration.Field(client.TokenLifetimeField),
+		"attributeContract":       toInterfaceSlice(manager.AttributeContract.AttributeNames()),
+		"defaultSubjectAttribute": manager.AttributeContract.DefaultSubjectAttribute,
+		"restrictClients":         manager.AccessControlSettings.RestrictClients,
+	}
+
+	newResource, err := resource.NewAppResource(
+		manager.Name,
+		resourceTypeAccessTokenManager,
+		manager.ID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *accessTokenManagerBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeAccessTokenManager
+}
+
+func (o *accessTokenManagerBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	managers, err := o.client.GetAccessTokenManagers(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeAccessTokenManager, fmt.Errorf("failed to list access token managers: %w", err))
+	}
+
+	rv := make([]*v2.Resource, 0, len(managers))
+	for _, manager := range managers {
+		newResource, err := accessTokenManagerResource(&manager)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements returns the entitlement of OAuth clients allowed to obtain tokens from the manager.
+func (o *accessTokenManagerBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			accessTokenManagerEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeOAuthClient),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("Use %s", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Allowed to obtain access tokens from the %s access token manager", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants returns the OAuth clients allowed to obtain tokens from the manager. A manager that does
+// not restrict its clients may be used by every client, those grants are flagged with the
+// unrestricted grant metadata. Clients restricted to another default manager are left out either way.
+func (o *accessTokenManagerBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	manager, err := o.client.GetAccessTokenManager(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeAccessTokenManager, err)
+	}
+	settings, err := o.client.GetAccessTokenManagerSettings(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeAccessTokenManager, err)
+	}
+	defaultManagerID := ""
+	if settings.DefaultAccessTokenManagerRef != nil {
+		defaultManagerID = settings.DefaultAccessTokenManagerRef.ID
+	}
+
+	oauthClients, err := o.client.ListOAuthClients(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeAccessTokenManager, fmt.Errorf("failed to list oauth clients: %w", err))
+	}
+	pinnedElsewhere := make(map[string]bool)
+	for _, oauthClient := range oauthClients {
+		if oauthClient.PinnedToOtherAccessTokenManager(manager.ID, defaultManagerID) {
+			pinnedElsewhere[oauthClient.ClientID] = true
+		}
+	}
+
+	grants := make([]*v2.Grant, 0)
+	if manager.AccessControlSettings.RestrictClients {
+		for _, allowed := range manager.AccessControlSettings.AllowedClients {
+			if pinnedElsewhere[allowed.ID] {
+				continue
+			}
+			grants = append(grants, grant.NewGrant(
+				resource,
+				accessTokenManagerEntitlementName,
+				&v2.ResourceId{
+					ResourceType: resourceTypeOAuthClient.Id,
+					Resource:     allowed.ID,
+				},
+			))
+		}
+		return grants, "", nil, nil
+	}
+
+	for _, oauthClient := range oauthClients {
+		if pinnedElsewhere[oauthClient.ClientID] {
+			continue
+		}
+		grants = append(grants, grant.NewGrant(
+			resource,
+			accessTokenManagerEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeOAuthClient.Id,
+				Resource:     oauthClient.ClientID,
+			},
+			grant.WithGrantMetadata(map[string]interface{}{
+				"unrestricted": true,
+			}),
+		))
+	}
+
+	return grants, "", nil, nil
+}
+
+func newAccessTokenManagerBuilder(client *client.PingFederateClient) *accessTokenManagerBuilder {
+	return &accessTokenManagerBuilder{
+		resourceType: resourceTypeAccessTokenManager,
+		client:       client,
+	}
+}
@@ -0,0 +1,115 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type authenticationPolicyBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// authenticationPolicyResource convert an AuthenticationPolicyTree into a Resource.
+func authenticationPolicyResource(tree *client.AuthenticationPolicyTree) (*v2.Resource, error) {
+	displayName := tree.Name
+	if displayName == "" {
+		displayName = tree.ID
+	}
+
+	profile := map[string]interface{}{
+		"id":          tree.ID,
+		"name":        tree.Name,
+		"description": tree.Description,
+		"enabled":     tree.Enabled,
+		"idpAdapters": toInterfaceSlice(tree.IdpAdapterIDs()),
+	}
+
+	newResource, err := resource.NewAppResource(
+		displayName,
+		resourceTypeAuthenticationPolicy,
+		tree.ID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+		resource.WithDescription(tree.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *authenticationPolicyBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeAuthenticationPolicy
+}
+
+// List returns the trees of the default authentication policy.
+func (o *authenticationPolicyBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	trees, err := o.client.GetAuthenticationPolicyTrees(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeAuthenticationPolicy, fmt.Errorf("failed to list authentication policies: %w", err))
+	}
+
+	rv := make([]*v2.Resource, 0, len(trees))
+	for _, tree := range trees {
+		newResource, err := authenticationPolicyResource(&tree)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements always returns an empty slice for authentication policies.
+func (o *authenticationPolicyBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for authentication policies since they don't have any entitlements.
+func (o *authenticationPolicyBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	pToken *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return nil, "", nil, nil
+}
+
+func newAuthenticationPolicyBuilder(client *client.PingFederateClient) *authenticationPolicyBuilder {
+	return &authenticationPolicyBuilder{
+		resourceType: resourceTypeAuthenticationPolicy,
+		client:       client,
+	}
+}
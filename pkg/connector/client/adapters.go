@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// PasswordCredentialValidatorField is the configuration field of the IdP adapters holding the
+	// password credential validators they check credentials against.
+	PasswordCredentialValidatorField = "Password Credential Validator Instance"
+
+	AuthenticationSourceIdpAdapter = "IDP_ADAPTER"
+	TargetURLMappingSpAdapter      = "SP_ADAPTER"
+)
+
+// GetIdpAdapters retrieves the IdP adapters, the authentication sources PingFederate uses to
+// authenticate users as an IdP.
+func (c *PingFederateClient) GetIdpAdapters(ctx context.Context) ([]IdpAdapter, error) {
+	var response getIdpAdaptersResponse
+	err := c.doRequest(ctx, http.MethodGet, "/idp/adapters", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idp adapters: %w", err)
+	}
+	return response.Items, nil
+}
+
+// GetIdpAdapterDescriptors retrieves the IdP adapter plugin descriptors, which name the plugin
+// type of each adapter.
+func (c *PingFederateClient) GetIdpAdapterDescriptors(ctx context.Context) ([]PluginDescriptor, error) {
+	var response getPluginDescriptorsResponse
+	err := c.doRequest(ctx, http.MethodGet, "/idp/adapters/descriptors", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idp adapter descriptors: %w", err)
+	}
+	return response.Items, nil
+}
+
+// GetAuthenticationPolicyTrees retrieves the authentication policy trees of the default
+// authentication policy.
+func (c *PingFederateClient) GetAuthenticationPolicyTrees(ctx context.Context) ([]AuthenticationPolicyTree, error) {
+	var response AuthenticationPolicy
+	err := c.doRequest(ctx, http.MethodGet, "/authenticationPolicies/default", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authentication policies: %w", err)
+	}
+	return response.AuthnSelectionTrees, nil
+}
+
+// GetSpAdapters retrieves the SP adapters, the applications PingFederate delivers federated users
+// to as an SP.
+func (c *PingFederateClient) GetSpAdapters(ctx context.Context) ([]SpAdapter, error) {
+	var response getSpAdaptersResponse
+	err := c.doRequest(ctx, http.MethodGet, "/sp/adapters", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sp adapters: %w", err)
+	}
+	return response.Items, nil
+}
+
+// GetSpAdapterDescriptors retrieves the SP adapter plugin descriptors, which name the plugin
+// type of each adapter.
+func (c *PingFederateClient) GetSpAdapterDescriptors(ctx context.Context) ([]PluginDescriptor, error) {
+	var response getPluginDescriptorsResponse
+	err := c.doRequest(ctx, http.MethodGet, "/sp/adapters/descriptors", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sp adapter descriptors: %w", err)
+	}
+	return response.Items, nil
+}
+
+// GetTargetURLMappings retrieves the mappings of target URLs to the SP adapters and connections
+// that handle them.
+func (c *PingFederateClient) GetTargetURLMappings(ctx context.Context) ([]TargetURLMapping, error) {
+	var response getTargetURLMappingsResponse
+	err := c.doRequest(ctx, http.MethodGet, "/sp/targetUrlMappings", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target url mappings: %w", err)
+	}
+	return response.Items, nil
+}
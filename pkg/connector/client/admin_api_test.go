@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+)
+
+// newTestClient returns a client of the fake admin API authenticating as the connector account.
+func newTestClient(t *testing.T, api *fakeapi.Server) *PingFederateClient {
+	t.Helper()
+
+	c, err := New(context.Background(), api.URL, "connector", "password", nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// newFakeAdminAPI starts a fake admin API holding the given account documents.
+func newFakeAdminAPI(t *testing.T, accounts ...string) (*fakeapi.Server, *PingFederateClient) {
+	t.Helper()
+
+	api := fakeapi.New(t)
+	for _, account := range accounts {
+		var user PingFederateUser
+		err := json.Unmarshal([]byte(account), &user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		api.Set(accountPath(user.Username), account)
+	}
+	return api, newTestClient(t, api)
+}
+
+// puts returns the bodies of the PUT requests the fake admin API received.
+func puts(api *fakeapi.Server) []json.RawMessage {
+	bodies := make([]json.RawMessage, 0)
+	for _, r := range api.Requests(http.MethodPut, "") {
+		bodies = append(bodies, r.Body)
+	}
+	return bodies
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// DefaultCacheTTL bounds how long a listing of administrative accounts, OAuth clients or connections
+// is reused within a sync.
+const DefaultCacheTTL = 5 * time.Minute
+
+// accountsSnapshot is a point-in-time listing of administrative accounts indexed by role.
+type accountsSnapshot struct {
+	users     []PingFederateUser
+	roleIDs   []string
+	roleUsers map[string][]PingFederateUser
+	expiresAt time.Time
+}
+
+func newAccountsSnapshot(users []PingFederateUser, ttl time.Duration) *accountsSnapshot {
+	snapshot := &accountsSnapshot{
+		users:     users,
+		roleIDs:   make([]string, 0),
+		roleUsers: make(map[string][]PingFederateUser),
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	for _, user := range users {
+		for _, role := range user.Roles {
+			snapshot.add(role, user)
+		}
+		if user.IsAuditor {
+			snapshot.add(AuditorRole, user)
+		}
+	}
+
+	return snapshot
+}
+
+func (s *accountsSnapshot) add(roleID string, user PingFederateUser) {
+	if _, ok := s.roleUsers[roleID]; !ok {
+		s.roleIDs = append(s.roleIDs, roleID)
+	}
+	s.roleUsers[roleID] = append(s.roleUsers[roleID], user)
+}
+
+// listAccounts returns the cached listing of administrative accounts, fetching it when missing or expired.
+func (c *PingFederateClient) listAccounts(ctx context.Context) (*accountsSnapshot, error) {
+	c.accountsMtx.Lock()
+	defer c.accountsMtx.Unlock()
+
+	if c.accounts != nil && time.Now().Before(c.accounts.expiresAt) {
+		return c.accounts, nil
+	}
+
+	var response getAdminUsersResponse
+	err := c.doRequest(ctx, http.MethodGet, "/administrativeAccounts", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list administrative accounts: %w", err)
+	}
+
+	c.accounts = newAccountsSnapshot(response.Items, c.cacheTTL)
+	return c.accounts, nil
+}
+
+// InvalidateAccounts drops the cached listing of administrative accounts along with the
+// HTTP response cache, so the next read reflects changes made through the admin API.
+func (c *PingFederateClient) InvalidateAccounts(ctx context.Context) {
+	c.accountsMtx.Lock()
+	c.accounts = nil
+	c.accountsMtx.Unlock()
+
+	clearHTTPCaches(ctx)
+}
+
+// listingCache holds a point-in-time listing of every item of a paged endpoint, so the grants of
+// every resource of one type are computed from a single listing of the other type.
+type listingCache[T any] struct {
+	mtx       sync.Mutex
+	items     []T
+	expiresAt time.Time
+}
+
+// list returns the cached listing of the paged endpoint at path, reading every page when the
+// listing is missing or expired.
+func (l *listingCache[T]) list(ctx context.Context, c *PingFederateClient, path string) ([]T, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.items != nil && time.Now().Before(l.expiresAt) {
+		return l.items, nil
+	}
+
+	items := make([]T, 0)
+	for page := 1; page != 0; {
+		pageItems, nextPage, err := getPage[T](ctx, c, path, page, DefaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		page = nextPage
+	}
+
+	l.items = items
+	l.expiresAt = time.Now().Add(c.cacheTTL)
+	return l.items, nil
+}
+
+// invalidate drops the cached listing.
+func (l *listingCache[T]) invalidate() {
+	l.mtx.Lock()
+	l.items = nil
+	l.mtx.Unlock()
+}
+
+// clearMtx serializes clearing the HTTP caches. The cache behind uhttp drops a clear request that
+// arrives while another clear is in progress, leaving its caller waiting forever.
+var clearMtx sync.Mutex
+
+// clearHTTPCaches drops the cached responses of GET requests so the next read reaches the admin API.
+func clearHTTPCaches(ctx context.Context) {
+	clearMtx.Lock()
+	defer clearMtx.Unlock()
+
+	err := uhttp.ClearCaches(ctx)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("pingfederate-connector: failed to clear http caches", zap.Error(err))
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+)
+
+func TestListOAuthClientsReadsEveryPageOnce(t *testing.T) {
+	const total = DefaultPageSize + 20
+
+	api := fakeapi.New(t)
+	for i := range total {
+		api.Set(fmt.Sprintf("/oauth/clients/client-%d", i), fmt.Sprintf(`{"clientId":"client-%d"}`, i))
+	}
+	ctx := context.Background()
+	c := newTestClient(t, api)
+
+	for range 3 {
+		oauthClients, err := c.ListOAuthClients(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(oauthClients) != total {
+			t.Fatalf("listed %d clients, want %d", len(oauthClients), total)
+		}
+	}
+	if got := len(api.Requests(http.MethodGet, "/oauth/clients")); got != 2 {
+		t.Errorf("%d requests, want one per page", got)
+	}
+
+	c.oauthClients.invalidate()
+	clearHTTPCaches(ctx)
+	_, err := c.ListOAuthClients(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(api.Requests(http.MethodGet, "/oauth/clients")); got != 4 {
+		t.Errorf("%d requests after invalidating, want the listing read again", got)
+	}
+}
+
+func TestConcurrentCacheInvalidationsReturn(t *testing.T) {
+	ctx := context.Background()
+	_, c := newFakeAdminAPI(t, `{"username":"jdoe","active":true,"roles":[]}`)
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				c.InvalidateAccounts(ctx)
+				_, _ = c.GetUsers(ctx)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("concurrent invalidations did not return")
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const (
+	ProtocolSAML2   = "SAML2"
+	ProtocolWSFed   = "WS-Fed"
+	ProtocolWSTrust = "WS-Trust"
+	ProtocolOIDC    = "OIDC"
+)
+
+// protocolNames maps the browser SSO protocols of the admin API to their common names.
+var protocolNames = map[string]string{
+	"SAML20": ProtocolSAML2,
+	"SAML11": "SAML1.1",
+	"SAML10": "SAML1.0",
+	"WSFED":  ProtocolWSFed,
+	"OIDC":   ProtocolOIDC,
+}
+
+// protocolName returns the common name of a browser SSO protocol, or the protocol itself when
+// it is unknown.
+func protocolName(protocol string) string {
+	if name, ok := protocolNames[protocol]; ok {
+		return name
+	}
+	return protocol
+}
+
+// GetSpConnections retrieves one page of SP connections, the service providers PingFederate
+// federates to as an IdP.
+func (c *PingFederateClient) GetSpConnections(ctx context.Context, page int, pageSize int) ([]SpConnection, int, error) {
+	connections, nextPage, err := getPage[SpConnection](ctx, c, "/idp/spConnections", page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get sp connections: %w", err)
+	}
+	return connections, nextPage, nil
+}
+
+// GetIdpConnections retrieves one page of IdP connections, the partner identity providers
+// PingFederate trusts as an SP.
+func (c *PingFederateClient) GetIdpConnections(ctx context.Context, page int, pageSize int) ([]IdpConnection, int, error) {
+	connections, nextPage, err := getPage[IdpConnection](ctx, c, "/sp/idpConnections", page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get idp connections: %w", err)
+	}
+	return connections, nextPage, nil
+}
+
+// ListSpConnections returns every SP connection. The listing is cached for the rest of the sync.
+func (c *PingFederateClient) ListSpConnections(ctx context.Context) ([]SpConnection, error) {
+	connections, err := c.spConnections.list(ctx, c, "/idp/spConnections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sp connections: %w", err)
+	}
+	return connections, nil
+}
+
+// ListIdpConnections returns every IdP connection. The listing is cached for the rest of the sync.
+func (c *PingFederateClient) ListIdpConnections(ctx context.Context) ([]IdpConnection, error) {
+	connections, err := c.idpConnections.list(ctx, c, "/sp/idpConnections")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idp connections: %w", err)
+	}
+	return connections, nil
+}
+
+// SetSpConnectionActive enables or disables an SP connection, leaving the rest of the connection
+// untouched. It returns false when the connection is already in the requested state.
+func (c *PingFederateClient) SetSpConnectionActive(ctx context.Context, id string, active bool) (bool, error) {
+	return c.setConnectionActive(ctx, "/idp/spConnections/"+url.PathEscape(id), active)
+}
+
+// SetIdpConnectionActive enables or disables an IdP connection, leaving the rest of the connection
+// untouched. It returns false when the connection is already in the requested state.
+func (c *PingFederateClient) SetIdpConnectionActive(ctx context.Context, id string, active bool) (bool, error) {
+	return c.setConnectionActive(ctx, "/sp/idpConnections/"+url.PathEscape(id), active)
+}
+
+// setConnectionActive fetches the connection at path and writes it back with only its active
+// flag changed, so the settings the connector does not model survive the GET/PUT round trip.
+func (c *PingFederateClient) setConnectionActive(ctx context.Context, path string, active bool) (bool, error) {
+	mtx, _ := c.connLocks.LoadOrStore(path, &sync.Mutex{})
+	connMtx, _ := mtx.(*sync.Mutex)
+	connMtx.Lock()
+	defer connMtx.Unlock()
+
+	clearHTTPCaches(ctx)
+
+	var document map[string]json.RawMessage
+	err := c.doRequest(ctx, http.MethodGet, path, nil, &document)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var current bool
+	if raw, ok := document["active"]; ok {
+		err = json.Unmarshal(raw, &current)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode connection: %w", err)
+		}
+	}
+	if current == active {
+		return false, nil
+	}
+
+	err = setDocumentField(document, "active", active)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode connection: %w", err)
+	}
+
+	err = c.doRequest(ctx, http.MethodPut, path, document, nil)
+	clearHTTPCaches(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to update connection: %w", err)
+	}
+	return true, nil
+}
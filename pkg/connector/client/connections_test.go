@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+)
+
+// activeSpConnection carries settings the connector does not model, including credentials and
+// the attribute contract PingFederate needs to keep federating with the partner.
+const activeSpConnection = `{"type":"SP","id":"sp1","entityId":"https://sp.example.com","name":"Partner SP",` +
+	`"active":true,"loggingMode":"STANDARD","virtualEntityIds":["urn:idp:one"],` +
+	`"credentials":{"signingSettings":{"signingKeyPairRef":{"id":"key1"},"algorithm":"SHA256withRSA"}},` +
+	`"spBrowserSso":{"protocol":"SAML20","ssoServiceEndpoints":[{"binding":"POST","url":"/acs"}],` +
+	`"adapterMappings":[{"idpAdapterRef":{"id":"htmlform"},"attributeContractFulfillment":{"SAML_SUBJECT":{"source":{"type":"ADAPTER"},"value":"username"}}}]},` +
+	`"extendedProperties":{"team":{"values":["identity"]}}}`
+
+const disabledIdpConnection = `{"type":"IDP","id":"idp1","entityId":"https://idp.example.com","name":"Partner IdP",` +
+	`"active":false,"errorPageMsgId":"errorDetail.spSsoFailure",` +
+	`"idpBrowserSso":{"protocol":"SAML20","enabledProfiles":["SP_INITIATED_SSO"],"adapterMappings":[{"spAdapterRef":{"id":"opentoken"}}]}}`
+
+func TestConnectionRoundTripPreservesUnmodeledFields(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.New(t)
+	api.Set("/idp/spConnections/sp1", activeSpConnection)
+	api.Set("/sp/idpConnections/idp1", disabledIdpConnection)
+	c := newTestClient(t, api)
+
+	changed, err := c.SetSpConnectionActive(ctx, "sp1", false)
+	if err != nil {
+		t.Fatalf("SetSpConnectionActive() error = %v", err)
+	}
+	if !changed {
+		t.Fatal("SetSpConnectionActive() reported no change")
+	}
+	changed, err = c.SetIdpConnectionActive(ctx, "idp1", true)
+	if err != nil {
+		t.Fatalf("SetIdpConnectionActive() error = %v", err)
+	}
+	if !changed {
+		t.Fatal("SetIdpConnectionActive() reported no change")
+	}
+
+	requests := puts(api)
+	if len(requests) != 2 {
+		t.Fatalf("got %d PUT requests, want 2", len(requests))
+	}
+	assertOnlyFieldsChanged(t, activeSpConnection, requests[0], "active")
+	assertOnlyFieldsChanged(t, disabledIdpConnection, requests[1], "active")
+	if got := string(api.Document("/idp/spConnections/sp1")); !strings.Contains(got, `"active":false`) {
+		t.Errorf("sp connection = %s, want it disabled", got)
+	}
+	if got := string(api.Document("/sp/idpConnections/idp1")); !strings.Contains(got, `"active":true`) {
+		t.Errorf("idp connection = %s, want it enabled", got)
+	}
+}
+
+func TestConnectionAlreadyInStateIsNotWritten(t *testing.T) {
+	ctx := context.Background()
+	api := fakeapi.New(t)
+	api.Set("/idp/spConnections/sp1", activeSpConnection)
+	api.Set("/sp/idpConnections/idp1", disabledIdpConnection)
+	c := newTestClient(t, api)
+
+	changed, err := c.SetSpConnectionActive(ctx, "sp1", true)
+	if err != nil {
+		t.Fatalf("SetSpConnectionActive() error = %v", err)
+	}
+	if changed {
+		t.Error("SetSpConnectionActive() changed an active connection")
+	}
+	changed, err = c.SetIdpConnectionActive(ctx, "idp1", false)
+	if err != nil {
+		t.Fatalf("SetIdpConnectionActive() error = %v", err)
+	}
+	if changed {
+		t.Error("SetIdpConnectionActive() changed a disabled connection")
+	}
+
+	if requests := puts(api); len(requests) != 0 {
+		t.Errorf("got %d PUT requests, want none", len(requests))
+	}
+}
@@ -1,22 +1,599 @@
 package client
 
+import (
+	"encoding/json"
+	"slices"
+)
+
 type PingFederateUser struct {
 	Email             string   `json:"emailAddress,omitempty"`
-	EncryptedPassword string   `json:"encryptedPassword"`
+	EncryptedPassword string   `json:"encryptedPassword,omitempty"`
+	Password          string   `json:"password,omitempty"`
 	Username          string   `json:"username"`
 	PhoneNumber       string   `json:"phoneNumber,omitempty"`
 	Department        string   `json:"department,omitempty"`
-	Description       string   `json:"description"`
+	Description       string   `json:"description,omitempty"`
 	IsAuditor         bool     `json:"auditor"`
 	IsActive          bool     `json:"active"`
 	Roles             []string `json:"roles"`
 }
 
+// HeldRoles returns the roles of the account, including AUDITOR which the API exposes as a flag.
+func (u *PingFederateUser) HeldRoles() []string {
+	roles := make([]string, 0, len(u.Roles)+1)
+	roles = append(roles, u.Roles...)
+	if u.IsAuditor {
+		roles = append(roles, AuditorRole)
+	}
+	return roles
+}
+
+// accountDocument is an administrative account exactly as the API returned it, so that fields the
+// connector does not model survive a GET/PUT round trip.
+type accountDocument map[string]json.RawMessage
+
+// user decodes the modeled fields of the document.
+func (d accountDocument) user() (*PingFederateUser, error) {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var user PingFederateUser
+	err = json.Unmarshal(raw, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// patch writes back the roles, auditor and active fields that differ between before and after,
+// leaving every other field of the document untouched.
+func (d accountDocument) patch(before *PingFederateUser, after *PingFederateUser) error {
+	if !slices.Equal(before.Roles, after.Roles) {
+		if err := d.set("roles", after.Roles); err != nil {
+			return err
+		}
+	}
+	if before.IsAuditor != after.IsAuditor {
+		if err := d.set("auditor", after.IsAuditor); err != nil {
+			return err
+		}
+	}
+	if before.IsActive != after.IsActive {
+		if err := d.set("active", after.IsActive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accountChange is the change a read-modify-write cycle makes to the roles, auditor and active
+// fields of an account.
+type accountChange struct {
+	addRoles    []string
+	removeRoles []string
+	auditor     *bool
+	active      *bool
+}
+
+// diffAccount returns the change that turns before into after.
+func diffAccount(before *PingFederateUser, after *PingFederateUser) accountChange {
+	var change accountChange
+	for _, role := range after.Roles {
+		if !slices.Contains(before.Roles, role) {
+			change.addRoles = append(change.addRoles, role)
+		}
+	}
+	for _, role := range before.Roles {
+		if !slices.Contains(after.Roles, role) {
+			change.removeRoles = append(change.removeRoles, role)
+		}
+	}
+	if before.IsAuditor != after.IsAuditor {
+		change.auditor = &after.IsAuditor
+	}
+	if before.IsActive != after.IsActive {
+		change.active = &after.IsActive
+	}
+	return change
+}
+
+// empty reports whether the change leaves the account as it was.
+func (c accountChange) empty() bool {
+	return len(c.addRoles) == 0 && len(c.removeRoles) == 0 && c.auditor == nil && c.active == nil
+}
+
+// appliedTo reports whether the account reflects the change, whatever else changed on it.
+func (c accountChange) appliedTo(u *PingFederateUser) bool {
+	for _, role := range c.addRoles {
+		if !slices.Contains(u.Roles, role) {
+			return false
+		}
+	}
+	for _, role := range c.removeRoles {
+		if slices.Contains(u.Roles, role) {
+			return false
+		}
+	}
+	if c.auditor != nil && u.IsAuditor != *c.auditor {
+		return false
+	}
+	if c.active != nil && u.IsActive != *c.active {
+		return false
+	}
+	return true
+}
+
+func (d accountDocument) set(key string, value interface{}) error {
+	return setDocumentField(d, key, value)
+}
+
+// setDocumentField replaces one field of a raw API document.
+func setDocumentField(d map[string]json.RawMessage, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	d[key] = raw
+	return nil
+}
+
 type getAdminUsersResponse struct {
 	Items []PingFederateUser `json:"items"`
 }
 
 type PingFederateRole struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
+	Name        string `json:"name"`
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+}
+
+type PingFederateVersion struct {
+	Version string `json:"version"`
+}
+
+type UserCredentials struct {
+	CurrentPassword string `json:"currentPassword,omitempty"`
+	NewPassword     string `json:"newPassword"`
+}
+
+type OAuthClientAuth struct {
+	Type string `json:"type,omitempty"`
+}
+
+type OAuthClient struct {
+	ClientID         string          `json:"clientId"`
+	Name             string          `json:"name"`
+	Description      string          `json:"description,omitempty"`
+	Enabled          bool            `json:"enabled"`
+	GrantTypes       []string        `json:"grantTypes"`
+	RedirectURIs     []string        `json:"redirectUris"`
+	ClientAuth       OAuthClientAuth `json:"clientAuth"`
+	CreationDate     string          `json:"creationDate,omitempty"`
+	RestrictScopes   bool            `json:"restrictScopes"`
+	RestrictedScopes []string        `json:"restrictedScopes"`
+	ExclusiveScopes  []string        `json:"exclusiveScopes"`
+	// DefaultAccessTokenManagerRef is unset when the client uses the default manager of the server.
+	DefaultAccessTokenManagerRef        *ResourceLink `json:"defaultAccessTokenManagerRef,omitempty"`
+	RestrictToDefaultAccessTokenManager bool          `json:"restrictToDefaultAccessTokenManager"`
+}
+
+// oauthClientDocument is an OAuth client exactly as the API returned it, so that the settings the
+// connector does not model survive a GET/PUT round trip.
+type oauthClientDocument map[string]json.RawMessage
+
+// oauthClient decodes the modeled fields of the document.
+func (d oauthClientDocument) oauthClient() (*OAuthClient, error) {
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var oauthClient OAuthClient
+	err = json.Unmarshal(raw, &oauthClient)
+	if err != nil {
+		return nil, err
+	}
+	return &oauthClient, nil
+}
+
+// patchScopes writes back the scope fields that differ between before and after, leaving every
+// other field of the document untouched.
+func (d oauthClientDocument) patchScopes(before *OAuthClient, after *OAuthClient) error {
+	if before.RestrictScopes != after.RestrictScopes {
+		if err := setDocumentField(d, "restrictScopes", after.RestrictScopes); err != nil {
+			return err
+		}
+	}
+	if !slices.Equal(before.RestrictedScopes, after.RestrictedScopes) {
+		if err := setDocumentField(d, "restrictedScopes", after.RestrictedScopes); err != nil {
+			return err
+		}
+	}
+	if !slices.Equal(before.ExclusiveScopes, after.ExclusiveScopes) {
+		if err := setDocumentField(d, "exclusiveScopes", after.ExclusiveScopes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clientAuth decodes the client authentication settings of the document, keeping the fields the
+// connector does not model.
+func (d oauthClientDocument) clientAuth() (map[string]json.RawMessage, error) {
+	clientAuth := make(map[string]json.RawMessage)
+	raw, ok := d["clientAuth"]
+	if !ok {
+		return clientAuth, nil
+	}
+	err := json.Unmarshal(raw, &clientAuth)
+	if err != nil {
+		return nil, err
+	}
+	return clientAuth, nil
+}
+
+// ClientSecret is the body of the client secret endpoint of an OAuth client.
+type ClientSecret struct {
+	Secret          string `json:"secret,omitempty"`
+	EncryptedSecret string `json:"encryptedSecret,omitempty"`
+}
+
+// SecondarySecret is a previous client secret that stays valid until its expiry time.
+type SecondarySecret struct {
+	Secret          string `json:"secret,omitempty"`
+	EncryptedSecret string `json:"encryptedSecret,omitempty"`
+	ExpiryTime      string `json:"expiryTime"`
+}
+
+type OAuthScope struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Dynamic     bool   `json:"dynamic,omitempty"`
+}
+
+type AuthServerSettings struct {
+	Scopes          []OAuthScope `json:"scopes"`
+	ExclusiveScopes []OAuthScope `json:"exclusiveScopes"`
+}
+
+type ResourceLink struct {
+	ID       string `json:"id"`
+	Location string `json:"location,omitempty"`
+}
+
+type ConfigField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type ConfigRow struct {
+	Fields []ConfigField `json:"fields"`
+}
+
+type ConfigTable struct {
+	Name string      `json:"name"`
+	Rows []ConfigRow `json:"rows"`
+}
+
+type PluginConfiguration struct {
+	Tables []ConfigTable `json:"tables"`
+	Fields []ConfigField `json:"fields"`
+}
+
+// Field returns the value of the configuration field called name.
+func (c PluginConfiguration) Field(name string) string {
+	for _, field := range c.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// TableValues returns the values of the field called name in every row of every table.
+func (c PluginConfiguration) TableValues(name string) []string {
+	values := make([]string, 0)
+	for _, table := range c.Tables {
+		for _, row := range table.Rows {
+			for _, field := range row.Fields {
+				if field.Name == name && field.Value != "" {
+					values = append(values, field.Value)
+				}
+			}
+		}
+	}
+	return values
+}
+
+type AttributeContractEntry struct {
+	Name        string `json:"name"`
+	MultiValued bool   `json:"multiValued,omitempty"`
+}
+
+type AttributeContract struct {
+	CoreAttributes     []AttributeContractEntry `json:"coreAttributes"`
+	ExtendedAttributes []AttributeContractEntry `json:"extendedAttributes"`
+}
+
+// AttributeNames returns the names of the core and extended attributes of the contract.
+func (c AttributeContract) AttributeNames() []string {
+	names := make([]string, 0, len(c.CoreAttributes)+len(c.ExtendedAttributes))
+	for _, attribute := range c.CoreAttributes {
+		names = append(names, attribute.Name)
+	}
+	for _, attribute := range c.ExtendedAttributes {
+		names = append(names, attribute.Name)
+	}
+	return names
+}
+
+type AccessTokenAttributeContract struct {
+	AttributeContract
+	DefaultSubjectAttribute string `json:"defaultSubjectAttribute,omitempty"`
+}
+
+type AccessControlSettings struct {
+	RestrictClients bool           `json:"restrictClients"`
+	AllowedClients  []ResourceLink `json:"allowedClients"`
+}
+
+type AccessTokenManager struct {
+	ID                    string                       `json:"id"`
+	Name                  string                       `json:"name"`
+	PluginDescriptorRef   ResourceLink                 `json:"pluginDescriptorRef"`
+	Configuration         PluginConfiguration          `json:"configuration"`
+	AttributeContract     AccessTokenAttributeContract `json:"attributeContract"`
+	AccessControlSettings AccessControlSettings        `json:"accessControlSettings"`
+}
+
+type AccessTokenManagerSettings struct {
+	DefaultAccessTokenManagerRef *ResourceLink `json:"defaultAccessTokenManagerRef,omitempty"`
+}
+
+type getAccessTokenManagersResponse struct {
+	Items []AccessTokenManager `json:"items"`
+}
+
+type ContactInfo struct {
+	Company   string `json:"company,omitempty"`
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"firstName,omitempty"`
+	LastName  string `json:"lastName,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+}
+
+type SigningSettings struct {
+	SigningKeyPairRef ResourceLink `json:"signingKeyPairRef"`
+	Algorithm         string       `json:"algorithm,omitempty"`
+}
+
+type ConnectionCredentials struct {
+	SigningSettings *SigningSettings `json:"signingSettings,omitempty"`
+}
+
+type IdpAdapterMapping struct {
+	IdpAdapterRef ResourceLink `json:"idpAdapterRef"`
+}
+
+type SpBrowserSso struct {
+	Protocol        string              `json:"protocol"`
+	AdapterMappings []IdpAdapterMapping `json:"adapterMappings"`
+}
+
+type SpConnection struct {
+	ID           string                `json:"id"`
+	EntityID     string                `json:"entityId"`
+	Name         string                `json:"name"`
+	Active       bool                  `json:"active"`
+	BaseURL      string                `json:"baseUrl,omitempty"`
+	ContactInfo  ContactInfo           `json:"contactInfo"`
+	Credentials  ConnectionCredentials `json:"credentials"`
+	SpBrowserSso *SpBrowserSso         `json:"spBrowserSso,omitempty"`
+	WsTrust      json.RawMessage       `json:"wsTrust,omitempty"`
+}
+
+// Protocols returns the federation protocols the connection is configured for.
+func (c *SpConnection) Protocols() []string {
+	protocols := make([]string, 0)
+	if c.SpBrowserSso != nil {
+		protocols = append(protocols, protocolName(c.SpBrowserSso.Protocol))
+	}
+	if c.WsTrust != nil {
+		protocols = append(protocols, ProtocolWSTrust)
+	}
+	return protocols
+}
+
+// IdpAdapterIDs returns the IDs of the IdP adapters the connection authenticates users with.
+func (c *SpConnection) IdpAdapterIDs() []string {
+	ids := make([]string, 0)
+	if c.SpBrowserSso == nil {
+		return ids
+	}
+	for _, mapping := range c.SpBrowserSso.AdapterMappings {
+		ids = append(ids, mapping.IdpAdapterRef.ID)
+	}
+	return ids
+}
+
+// SigningKeyPairID returns the ID of the key pair the connection signs with, if any.
+func (c *SpConnection) SigningKeyPairID() string {
+	if c.Credentials.SigningSettings == nil {
+		return ""
+	}
+	return c.Credentials.SigningSettings.SigningKeyPairRef.ID
+}
+
+type JitUserRepository struct {
+	Type string `json:"type"`
+}
+
+type JitProvisioning struct {
+	UserRepository JitUserRepository `json:"userRepository"`
+	EventTrigger   string            `json:"eventTrigger,omitempty"`
+	ErrorHandling  string            `json:"errorHandling,omitempty"`
+}
+
+type SpAdapterMapping struct {
+	SpAdapterRef ResourceLink `json:"spAdapterRef"`
+}
+
+type AuthenticationPolicyContractMapping struct {
+	AuthenticationPolicyContractRef ResourceLink `json:"authenticationPolicyContractRef"`
+}
+
+type IdpBrowserSso struct {
+	Protocol                             string                                `json:"protocol"`
+	AdapterMappings                      []SpAdapterMapping                    `json:"adapterMappings"`
+	AuthenticationPolicyContractMappings []AuthenticationPolicyContractMapping `json:"authenticationPolicyContractMappings"`
+	JitProvisioning                      *JitProvisioning                      `json:"jitProvisioning,omitempty"`
+}
+
+type IdpConnection struct {
+	ID            string          `json:"id"`
+	EntityID      string          `json:"entityId"`
+	Name          string          `json:"name"`
+	Active        bool            `json:"active"`
+	BaseURL       string          `json:"baseUrl,omitempty"`
+	IdpBrowserSso *IdpBrowserSso  `json:"idpBrowserSso,omitempty"`
+	WsTrust       json.RawMessage `json:"wsTrust,omitempty"`
+}
+
+// Protocols returns the federation protocols the connection is configured for.
+func (c *IdpConnection) Protocols() []string {
+	protocols := make([]string, 0)
+	if c.IdpBrowserSso != nil {
+		protocols = append(protocols, protocolName(c.IdpBrowserSso.Protocol))
+	}
+	if c.WsTrust != nil {
+		protocols = append(protocols, ProtocolWSTrust)
+	}
+	return protocols
+}
+
+// SpAdapterIDs returns the IDs of the SP adapters the connection routes users into.
+func (c *IdpConnection) SpAdapterIDs() []string {
+	ids := make([]string, 0)
+	if c.IdpBrowserSso == nil {
+		return ids
+	}
+	for _, mapping := range c.IdpBrowserSso.AdapterMappings {
+		ids = append(ids, mapping.SpAdapterRef.ID)
+	}
+	return ids
+}
+
+// AuthenticationPolicyContractIDs returns the IDs of the authentication policy contracts the
+// connection maps users into.
+func (c *IdpConnection) AuthenticationPolicyContractIDs() []string {
+	ids := make([]string, 0)
+	if c.IdpBrowserSso == nil {
+		return ids
+	}
+	for _, mapping := range c.IdpBrowserSso.AuthenticationPolicyContractMappings {
+		ids = append(ids, mapping.AuthenticationPolicyContractRef.ID)
+	}
+	return ids
+}
+
+// JitProvisioning returns the just-in-time provisioning settings of the connection, or nil when
+// it does not provision users.
+func (c *IdpConnection) JitProvisioning() *JitProvisioning {
+	if c.IdpBrowserSso == nil {
+		return nil
+	}
+	return c.IdpBrowserSso.JitProvisioning
+}
+
+type PluginDescriptor struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ClassName string `json:"className,omitempty"`
+}
+
+type getPluginDescriptorsResponse struct {
+	Items []PluginDescriptor `json:"items"`
+}
+
+type IdpAdapter struct {
+	ID                  string              `json:"id"`
+	Name                string              `json:"name"`
+	PluginDescriptorRef ResourceLink        `json:"pluginDescriptorRef"`
+	Configuration       PluginConfiguration `json:"configuration"`
+	AttributeContract   AttributeContract   `json:"attributeContract"`
+}
+
+type getIdpAdaptersResponse struct {
+	Items []IdpAdapter `json:"items"`
+}
+
+type AuthenticationSource struct {
+	Type      string       `json:"type"`
+	SourceRef ResourceLink `json:"sourceRef"`
+}
+
+type PolicyAction struct {
+	Type                 string                `json:"type"`
+	AuthenticationSource *AuthenticationSource `json:"authenticationSource,omitempty"`
+}
+
+type AuthenticationPolicyTreeNode struct {
+	Action   PolicyAction                   `json:"action"`
+	Children []AuthenticationPolicyTreeNode `json:"children"`
+}
+
+type AuthenticationPolicyTree struct {
+	ID          string                        `json:"id"`
+	Name        string                        `json:"name"`
+	Description string                        `json:"description,omitempty"`
+	Enabled     bool                          `json:"enabled"`
+	RootNode    *AuthenticationPolicyTreeNode `json:"rootNode,omitempty"`
+}
+
+// IdpAdapterIDs returns the IDs of the IdP adapters the policy authenticates users with, in the
+// order they appear in the tree.
+func (t *AuthenticationPolicyTree) IdpAdapterIDs() []string {
+	ids := make([]string, 0)
+	var walk func(node *AuthenticationPolicyTreeNode)
+	walk = func(node *AuthenticationPolicyTreeNode) {
+		source := node.Action.AuthenticationSource
+		if source != nil && source.Type == AuthenticationSourceIdpAdapter && !slices.Contains(ids, source.SourceRef.ID) {
+			ids = append(ids, source.SourceRef.ID)
+		}
+		for i := range node.Children {
+			walk(&node.Children[i])
+		}
+	}
+	if t.RootNode != nil {
+		walk(t.RootNode)
+	}
+	return ids
+}
+
+type AuthenticationPolicy struct {
+	AuthnSelectionTrees []AuthenticationPolicyTree `json:"authnSelectionTrees"`
+}
+
+type SpAdapter struct {
+	ID                  string              `json:"id"`
+	Name                string              `json:"name"`
+	PluginDescriptorRef ResourceLink        `json:"pluginDescriptorRef"`
+	Configuration       PluginConfiguration `json:"configuration"`
+	AttributeContract   AttributeContract   `json:"attributeContract"`
+}
+
+type getSpAdaptersResponse struct {
+	Items []SpAdapter `json:"items"`
+}
+
+type TargetURLMapping struct {
+	URL  string       `json:"url"`
+	Type string       `json:"type"`
+	Ref  ResourceLink `json:"ref"`
+}
+
+type getTargetURLMappingsResponse struct {
+	Items []TargetURLMapping `json:"items"`
 }
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// unmodeledAccount carries fields PingFederateUser does not model and no description.
+const unmodeledAccount = `{"username":"jdoe","active":true,"auditor":false,"roles":["USER_ADMINISTRATOR"],` +
+	`"emailAddress":"jdoe@example.com","department":"IT","phoneNumber":"555-0100",` +
+	`"encryptedPassword":"OBF:JWE:eyJhbGciOiJkaXIifQ..abc","futureSetting":{"enabled":true,"values":[1,2,3]}}`
+
+// assertOnlyFieldsChanged fails unless put holds exactly the fields of original, byte for byte,
+// except for the changed fields.
+func assertOnlyFieldsChanged(t *testing.T, original string, put json.RawMessage, changed ...string) {
+	t.Helper()
+
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(original), &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(put, &after); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range before {
+		if slices.Contains(changed, key) {
+			continue
+		}
+		if !bytes.Equal(after[key], value) {
+			t.Errorf("field %s = %s, want %s", key, after[key], value)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			t.Errorf("unexpected field %s = %s", key, after[key])
+		}
+	}
+}
+
+func TestAccountRoundTripPreservesUnmodeledFields(t *testing.T) {
+	ctx := context.Background()
+	api, c := newFakeAdminAPI(t, unmodeledAccount)
+
+	granted, err := c.AddUserToRole(ctx, "jdoe", CryptoAdministratorRole, false)
+	if err != nil {
+		t.Fatalf("AddUserToRole() error = %v", err)
+	}
+	if !granted {
+		t.Fatal("AddUserToRole() reported no change")
+	}
+	if len(puts(api)) != 1 {
+		t.Fatalf("got %d PUT requests, want 1", len(puts(api)))
+	}
+	assertOnlyFieldsChanged(t, unmodeledAccount, puts(api)[0], "roles")
+
+	revoked, err := c.RemoveUserFromRole(ctx, "jdoe", CryptoAdministratorRole)
+	if err != nil {
+		t.Fatalf("RemoveUserFromRole() error = %v", err)
+	}
+	if !revoked {
+		t.Fatal("RemoveUserFromRole() reported no change")
+	}
+	if len(puts(api)) != 2 {
+		t.Fatalf("got %d PUT requests, want 2", len(puts(api)))
+	}
+	// Revoking the granted role restores the original document.
+	assertOnlyFieldsChanged(t, unmodeledAccount, puts(api)[1])
+}
+
+func TestAuditorRoundTripPreservesUnmodeledFields(t *testing.T) {
+	ctx := context.Background()
+	account := `{"username":"auditor","active":true,"auditor":false,"roles":[],"futureSetting":"kept"}`
+	api, c := newFakeAdminAPI(t, account)
+
+	_, err := c.AddUserToRole(ctx, "auditor", AuditorRole, false)
+	if err != nil {
+		t.Fatalf("AddUserToRole() error = %v", err)
+	}
+	assertOnlyFieldsChanged(t, account, puts(api)[0], "auditor")
+
+	_, err = c.RemoveUserFromRole(ctx, "auditor", AuditorRole)
+	if err != nil {
+		t.Fatalf("RemoveUserFromRole() error = %v", err)
+	}
+	assertOnlyFieldsChanged(t, account, puts(api)[1])
+}
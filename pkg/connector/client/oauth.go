@@ -0,0 +1,273 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+const ClientAuthTypeSecret = "SECRET"
+
+// GetOAuthClients retrieves one page of OAuth clients. Pages are numbered from 1, and the
+// returned next page is 0 once the last page has been read.
+func (c *PingFederateClient) GetOAuthClients(ctx context.Context, page int, pageSize int) ([]OAuthClient, int, error) {
+	oauthClients, nextPage, err := getPage[OAuthClient](ctx, c, "/oauth/clients", page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get oauth clients: %w", err)
+	}
+	return oauthClients, nextPage, nil
+}
+
+// ListOAuthClients returns every OAuth client. The listing is cached for the rest of the sync and
+// dropped when the connector changes the scopes of a client.
+func (c *PingFederateClient) ListOAuthClients(ctx context.Context) ([]OAuthClient, error) {
+	oauthClients, err := c.oauthClients.list(ctx, c, "/oauth/clients")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth clients: %w", err)
+	}
+	return oauthClients, nil
+}
+
+// PinnedToOtherAccessTokenManager reports whether the client may only obtain tokens from its default
+// access token manager and that manager is not managerID. Clients without a default manager of
+// their own use defaultManagerID, the default manager of the server.
+func (o *OAuthClient) PinnedToOtherAccessTokenManager(managerID string, defaultManagerID string) bool {
+	if !o.RestrictToDefaultAccessTokenManager {
+		return false
+	}
+	pinned := defaultManagerID
+	if o.DefaultAccessTokenManagerRef != nil && o.DefaultAccessTokenManagerRef.ID != "" {
+		pinned = o.DefaultAccessTokenManagerRef.ID
+	}
+	return pinned != managerID
+}
+
+// GetAuthServerSettings retrieves the authorization server settings, which hold the common and
+// exclusive scopes OAuth clients may request.
+func (c *PingFederateClient) GetAuthServerSettings(ctx context.Context) (*AuthServerSettings, error) {
+	var response AuthServerSettings
+	err := c.doRequest(ctx, http.MethodGet, "/oauth/authServerSettings", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authorization server settings: %w", err)
+	}
+	return &response, nil
+}
+
+// lockOAuthClient serializes read-modify-write cycles on one OAuth client.
+func (c *PingFederateClient) lockOAuthClient(clientID string) func() {
+	mtx, _ := c.clientLocks.LoadOrStore(clientID, &sync.Mutex{})
+	clientMtx, _ := mtx.(*sync.Mutex)
+	clientMtx.Lock()
+	return clientMtx.Unlock
+}
+
+// updateOAuthClientScopes fetches an OAuth client, applies mutate to it and writes it back. Only the
+// scope fields changed by mutate are patched into the document sent back. It returns false without
+// writing when mutate leaves the scopes unchanged.
+func (c *PingFederateClient) updateOAuthClientScopes(
+	ctx context.Context,
+	clientID string,
+	mutate func(oauthClient *OAuthClient, settings *AuthServerSettings) error,
+) (bool, error) {
+	unlock := c.lockOAuthClient(clientID)
+	defer unlock()
+
+	clearHTTPCaches(ctx)
+
+	settings, err := c.GetAuthServerSettings(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	path := "/oauth/clients/" + url.PathEscape(clientID)
+	var document oauthClientDocument
+	err = c.doRequest(ctx, http.MethodGet, path, nil, &document)
+	if err != nil {
+		return false, fmt.Errorf("failed to get oauth client: %w", err)
+	}
+	before, err := document.oauthClient()
+	if err != nil {
+		return false, fmt.Errorf("failed to decode oauth client: %w", err)
+	}
+	oauthClient, err := document.oauthClient()
+	if err != nil {
+		return false, fmt.Errorf("failed to decode oauth client: %w", err)
+	}
+
+	err = mutate(oauthClient, settings)
+	if err != nil {
+		return false, err
+	}
+	if before.RestrictScopes == oauthClient.RestrictScopes &&
+		slices.Equal(before.RestrictedScopes, oauthClient.RestrictedScopes) &&
+		slices.Equal(before.ExclusiveScopes, oauthClient.ExclusiveScopes) {
+		return false, nil
+	}
+
+	err = document.patchScopes(before, oauthClient)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode oauth client: %w", err)
+	}
+
+	err = c.doRequest(ctx, http.MethodPut, path, document, nil)
+	c.oauthClients.invalidate()
+	clearHTTPCaches(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to update oauth client: %w", err)
+	}
+	return true, nil
+}
+
+// isExclusiveScope reports whether scope is an exclusive scope of the authorization server, and
+// fails when it is neither a common nor an exclusive scope.
+func (s *AuthServerSettings) isExclusiveScope(scope string) (bool, error) {
+	hasName := func(name string) func(OAuthScope) bool {
+		return func(candidate OAuthScope) bool { return candidate.Name == name }
+	}
+	if slices.ContainsFunc(s.ExclusiveScopes, hasName(scope)) {
+		return true, nil
+	}
+	if slices.ContainsFunc(s.Scopes, hasName(scope)) {
+		return false, nil
+	}
+	return false, fmt.Errorf("%s is not a scope of the authorization server", scope)
+}
+
+// AddScopeToOAuthClient allows an OAuth client to request scope. Exclusive scopes are added to
+// the exclusive scopes of the client, common scopes to its restricted scopes. It returns false when
+// the client may already request the scope, including common scopes of clients that do not
+// restrict their scopes.
+func (c *PingFederateClient) AddScopeToOAuthClient(ctx context.Context, clientID string, scope string) (bool, error) {
+	return c.updateOAuthClientScopes(ctx, clientID, func(oauthClient *OAuthClient, settings *AuthServerSettings) error {
+		exclusive, err := settings.isExclusiveScope(scope)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case exclusive && !slices.Contains(oauthClient.ExclusiveScopes, scope):
+			oauthClient.ExclusiveScopes = append(oauthClient.ExclusiveScopes, scope)
+		case !exclusive && oauthClient.RestrictScopes && !slices.Contains(oauthClient.RestrictedScopes, scope):
+			oauthClient.RestrictedScopes = append(oauthClient.RestrictedScopes, scope)
+		}
+		return nil
+	})
+}
+
+// RemoveScopeFromOAuthClient stops an OAuth client from requesting scope. A client that does not
+// restrict its scopes may request every common scope, so removing one of them fails rather than
+// silently restricting the client. It returns false when the client could not request the scope.
+func (c *PingFederateClient) RemoveScopeFromOAuthClient(ctx context.Context, clientID string, scope string) (bool, error) {
+	return c.updateOAuthClientScopes(ctx, clientID, func(oauthClient *OAuthClient, settings *AuthServerSettings) error {
+		exclusive, err := settings.isExclusiveScope(scope)
+		if err != nil {
+			return err
+		}
+
+		if exclusive {
+			oauthClient.ExclusiveScopes = slices.DeleteFunc(oauthClient.ExclusiveScopes, func(s string) bool { return s == scope })
+			return nil
+		}
+		if !oauthClient.RestrictScopes {
+			return fmt.Errorf("cannot remove %s from %s: the client is not restricted to specific scopes", scope, clientID)
+		}
+		oauthClient.RestrictedScopes = slices.DeleteFunc(oauthClient.RestrictedScopes, func(s string) bool { return s == scope })
+		return nil
+	})
+}
+
+// RotateOAuthClientSecret replaces the secret of an OAuth client that authenticates with a client
+// secret. When overlap is positive, the current secret is kept as a secondary secret for that long
+// so consumers can roll over to the new secret, and expired secondary secrets are dropped. The new
+// secret and the secondary secrets are written in a single update, so a failed rotation leaves the
+// client as it was.
+func (c *PingFederateClient) RotateOAuthClientSecret(ctx context.Context, clientID string, secret string, overlap time.Duration) error {
+	unlock := c.lockOAuthClient(clientID)
+	defer unlock()
+
+	clearHTTPCaches(ctx)
+	defer clearHTTPCaches(ctx)
+
+	path := "/oauth/clients/" + url.PathEscape(clientID)
+	var document oauthClientDocument
+	err := c.doRequest(ctx, http.MethodGet, path, nil, &document)
+	if err != nil {
+		return fmt.Errorf("failed to get oauth client: %w", err)
+	}
+	oauthClient, err := document.oauthClient()
+	if err != nil {
+		return fmt.Errorf("failed to decode oauth client: %w", err)
+	}
+	if oauthClient.ClientAuth.Type != ClientAuthTypeSecret {
+		return fmt.Errorf("cannot rotate the secret of %s: the client authenticates with %q, not a client secret", clientID, oauthClient.ClientAuth.Type)
+	}
+
+	clientAuth, err := document.clientAuth()
+	if err != nil {
+		return fmt.Errorf("failed to decode oauth client authentication: %w", err)
+	}
+	if overlap > 0 {
+		err = c.keepSecondarySecret(ctx, path, clientAuth, overlap)
+		if err != nil {
+			return err
+		}
+	}
+
+	delete(clientAuth, "encryptedSecret")
+	err = setDocumentField(clientAuth, "secret", secret)
+	if err != nil {
+		return fmt.Errorf("failed to encode oauth client secret: %w", err)
+	}
+	err = setDocumentField(document, "clientAuth", clientAuth)
+	if err != nil {
+		return fmt.Errorf("failed to encode oauth client authentication: %w", err)
+	}
+
+	err = c.doRequest(ctx, http.MethodPut, path, document, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update oauth client secret: %w", err)
+	}
+	return nil
+}
+
+// keepSecondarySecret adds the current secret of an OAuth client to the secondary secrets of its
+// client authentication settings, expiring after overlap.
+func (c *PingFederateClient) keepSecondarySecret(ctx context.Context, path string, clientAuth map[string]json.RawMessage, overlap time.Duration) error {
+	var current ClientSecret
+	err := c.doRequest(ctx, http.MethodGet, path+"/clientAuth/clientSecret", nil, &current)
+	if err != nil {
+		return fmt.Errorf("failed to get oauth client secret: %w", err)
+	}
+	if current.EncryptedSecret == "" {
+		return nil
+	}
+
+	var secondarySecrets []SecondarySecret
+	if raw, ok := clientAuth["secondarySecrets"]; ok {
+		err = json.Unmarshal(raw, &secondarySecrets)
+		if err != nil {
+			return fmt.Errorf("failed to decode oauth client secondary secrets: %w", err)
+		}
+	}
+
+	now := time.Now()
+	secondarySecrets = slices.DeleteFunc(secondarySecrets, func(s SecondarySecret) bool {
+		expiry, err := time.Parse(time.RFC3339, s.ExpiryTime)
+		return err == nil && expiry.Before(now)
+	})
+	secondarySecrets = append(secondarySecrets, SecondarySecret{
+		EncryptedSecret: current.EncryptedSecret,
+		ExpiryTime:      now.Add(overlap).UTC().Format(time.RFC3339),
+	})
+
+	err = setDocumentField(clientAuth, "secondarySecrets", secondarySecrets)
+	if err != nil {
+		return fmt.Errorf("failed to encode oauth client secondary secrets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+)
+
+const secretClient = `{
+	"clientId": "app",
+	"name": "App",
+	"grantTypes": ["CLIENT_CREDENTIALS"],
+	"clientAuth": {
+		"type": "SECRET",
+		"encryptedSecret": "OLD",
+		"secondarySecrets": [{"encryptedSecret": "EXPIRED", "expiryTime": "2001-01-01T00:00:00Z"}],
+		"enforceReplayPrevention": false
+	},
+	"jwksSettings": {"jwksUrl": "https://app.example.com/jwks"}
+}`
+
+const appPath = "/oauth/clients/app"
+
+// newFakeOAuthClients starts a fake admin API holding the app client and its client secret.
+func newFakeOAuthClients(t *testing.T) (*fakeapi.Server, *PingFederateClient) {
+	t.Helper()
+
+	api := fakeapi.New(t)
+	api.Set(appPath, secretClient)
+	api.Set(appPath+"/clientAuth/clientSecret", `{"encryptedSecret": "OLD"}`)
+	return api, newTestClient(t, api)
+}
+
+func TestRotateOAuthClientSecretWritesOnce(t *testing.T) {
+	api, c := newFakeOAuthClients(t)
+
+	err := c.RotateOAuthClientSecret(context.Background(), "app", "NEW", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := api.Requests(http.MethodPut, "")
+	if len(requests) != 1 || requests[0].Path != appPath {
+		t.Fatalf("PUTs %v, want one update of the client", requests)
+	}
+	var put map[string]json.RawMessage
+	err = json.Unmarshal(requests[0].Body, &put)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(put["jwksSettings"]) != `{"jwksUrl":"https://app.example.com/jwks"}` {
+		t.Errorf("jwksSettings = %s, want it kept", put["jwksSettings"])
+	}
+
+	var clientAuth struct {
+		Secret                  string            `json:"secret"`
+		EncryptedSecret         string            `json:"encryptedSecret"`
+		SecondarySecrets        []SecondarySecret `json:"secondarySecrets"`
+		EnforceReplayPrevention *bool             `json:"enforceReplayPrevention"`
+	}
+	err = json.Unmarshal(put["clientAuth"], &clientAuth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clientAuth.Secret != "NEW" || clientAuth.EncryptedSecret != "" {
+		t.Errorf("clientAuth = %s, want the new secret in place of the encrypted one", put["clientAuth"])
+	}
+	if len(clientAuth.SecondarySecrets) != 1 || clientAuth.SecondarySecrets[0].EncryptedSecret != "OLD" {
+		t.Errorf("secondarySecrets = %+v, want only the previous secret", clientAuth.SecondarySecrets)
+	}
+	if clientAuth.EnforceReplayPrevention == nil {
+		t.Errorf("clientAuth = %s, want enforceReplayPrevention kept", put["clientAuth"])
+	}
+}
+
+func TestRotateOAuthClientSecretFailureLeavesClient(t *testing.T) {
+	api, c := newFakeOAuthClients(t)
+	api.Fail(http.MethodPut, appPath, http.StatusUnprocessableEntity)
+
+	err := c.RotateOAuthClientSecret(context.Background(), "app", "NEW", time.Hour)
+	if err == nil {
+		t.Fatal("rotation succeeded, want the failed update reported")
+	}
+	if len(puts(api)) != 1 {
+		t.Errorf("%d PUTs, want a single attempt", len(puts(api)))
+	}
+	if string(api.Document(appPath)) != secretClient {
+		t.Errorf("client = %s, want it unchanged", api.Document(appPath))
+	}
+}
+
+const (
+	scopeSettings = `{"scopes":[{"name":"openid"},{"name":"profile"}],"exclusiveScopes":[{"name":"admin"}]}`
+
+	restrictedClient = `{"clientId":"restricted","name":"Restricted","restrictScopes":true,` +
+		`"restrictedScopes":["openid"],"exclusiveScopes":[],"clientAuth":{"type":"SECRET","encryptedSecret":"X"},` +
+		`"oidcPolicy":{"grantAccessSessionRevocationApi":false,"pingAccessLogoutCapable":true},"futureSetting":[1,2]}`
+
+	unrestrictedClient = `{"clientId":"unrestricted","name":"Unrestricted","restrictScopes":false,` +
+		`"restrictedScopes":[],"exclusiveScopes":["admin"],"persistentGrantExpirationType":"SERVER_DEFAULT"}`
+)
+
+func TestOAuthClientScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   string
+		add      bool
+		scope    string
+		want     bool
+		wantErr  bool
+		field    string
+		wantJSON string
+	}{
+		{
+			name:     "add common scope to a restricted client",
+			client:   restrictedClient,
+			add:      true,
+			scope:    "profile",
+			want:     true,
+			field:    "restrictedScopes",
+			wantJSON: `["openid","profile"]`,
+		},
+		{
+			name:     "add exclusive scope",
+			client:   restrictedClient,
+			add:      true,
+			scope:    "admin",
+			want:     true,
+			field:    "exclusiveScopes",
+			wantJSON: `["admin"]`,
+		},
+		{
+			name:   "add held common scope",
+			client: restrictedClient,
+			add:    true,
+			scope:  "openid",
+			want:   false,
+		},
+		{
+			name:   "add common scope to an unrestricted client",
+			client: unrestrictedClient,
+			add:    true,
+			scope:  "profile",
+			want:   false,
+		},
+		{
+			name:    "add unknown scope",
+			client:  restrictedClient,
+			add:     true,
+			scope:   "unknown",
+			wantErr: true,
+		},
+		{
+			name:     "remove common scope from a restricted client",
+			client:   restrictedClient,
+			scope:    "openid",
+			want:     true,
+			field:    "restrictedScopes",
+			wantJSON: `[]`,
+		},
+		{
+			name:     "remove exclusive scope",
+			client:   unrestrictedClient,
+			scope:    "admin",
+			want:     true,
+			field:    "exclusiveScopes",
+			wantJSON: `[]`,
+		},
+		{
+			name:   "remove exclusive scope the client does not hold",
+			client: restrictedClient,
+			scope:  "admin",
+			want:   false,
+		},
+		{
+			name:    "remove common scope from an unrestricted client",
+			client:  unrestrictedClient,
+			scope:   "openid",
+			wantErr: true,
+		},
+		{
+			name:    "remove unknown scope",
+			client:  unrestrictedClient,
+			scope:   "unknown",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			api := fakeapi.New(t)
+			api.Set("/oauth/authServerSettings", scopeSettings)
+			api.Set("/oauth/clients/client", tt.client)
+			c := newTestClient(t, api)
+
+			var got bool
+			var err error
+			if tt.add {
+				got, err = c.AddScopeToOAuthClient(ctx, "client", tt.scope)
+			} else {
+				got, err = c.RemoveScopeFromOAuthClient(ctx, "client", tt.scope)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("changed = %v, want %v", got, tt.want)
+			}
+
+			requests := puts(api)
+			if !tt.want {
+				if len(requests) != 0 {
+					t.Errorf("got %d PUT requests, want none", len(requests))
+				}
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("got %d PUT requests, want 1", len(requests))
+			}
+			assertOnlyFieldsChanged(t, tt.client, requests[0], tt.field)
+
+			var put map[string]json.RawMessage
+			err = json.Unmarshal(requests[0], &put)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(put[tt.field]) != tt.wantJSON {
+				t.Errorf("%s = %s, want %s", tt.field, put[tt.field], tt.wantJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items requested per page from paged endpoints.
+const DefaultPageSize = 100
+
+// getPage reads one page of items from a paged endpoint. Pages are numbered from 1, and the
+// returned next page is 0 once the last page has been read.
+func getPage[T any](ctx context.Context, c *PingFederateClient, path string, page int, pageSize int) ([]T, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("numberPerPage", strconv.Itoa(pageSize))
+
+	var response struct {
+		Items []T `json:"items"`
+	}
+	err := c.doRequestWithQuery(ctx, http.MethodGet, path, query, nil, &response)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(response.Items) < pageSize {
+		return response.Items, 0, nil
+	}
+	return response.Items, page + 1, nil
+}
@@ -5,20 +5,54 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type PingFederateClient struct {
-	baseURL  string
-	client   *uhttp.BaseHttpClient
-	Username string
-	Password string
+	baseURL     string
+	client      *uhttp.BaseHttpClient
+	oauth       *OAuthConfig
+	accountsMtx sync.Mutex
+	accounts    *accountsSnapshot
+	cacheTTL    time.Duration
+	userLocks   sync.Map
+	clientLocks sync.Map
+	connLocks   sync.Map
+	replicator  replicator
+	Username    string
+	Password    string
+
+	oauthClients   listingCache[OAuthClient]
+	spConnections  listingCache[SpConnection]
+	idpConnections listingCache[IdpConnection]
 }
 
+// OAuthConfig holds the client credentials used to obtain bearer tokens for the
+// admin API when native basic authentication is disabled.
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+}
+
+const (
+	// maxUpdateAttempts bounds how often a lost account update is applied again.
+	maxUpdateAttempts = 3
+)
+
 const (
-	APIPath     = "/pf-admin-api/v1"
-	AuditorRole = "AUDITOR"
+	APIPath               = "/pf-admin-api/v1"
+	AuditorRole           = "AUDITOR"
+	AdministratorRole     = "ADMINISTRATOR"
+	UserAdministratorRole = "USER_ADMINISTRATOR"
 )
 
 func New(
@@ -26,12 +60,15 @@ func New(
 	baseURL string,
 	username string,
 	password string,
+	oauth *OAuthConfig,
+	tlsConfig *TLSConfig,
+	replicateChanges bool,
 ) (*PingFederateClient, error) {
 	if baseURL == "" {
 		return nil, fmt.Errorf("base URL is required")
 	}
 
-	httpClient, err := uhttp.NewClient(ctx, uhttp.WithLogger(true, nil))
+	httpClient, err := newHTTPClient(ctx, oauth, tlsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -46,17 +83,87 @@ func New(
 		Password: password,
 		Username: username,
 		client:   client,
+		oauth:    oauth,
+		cacheTTL: DefaultCacheTTL,
+		replicator: replicator{
+			enabled: replicateChanges,
+			delay:   DefaultReplicationDelay,
+		},
 	}, nil
 }
 
+// newHTTPClient returns a plain HTTP client for basic authentication, or one that
+// fetches, caches and refreshes client credentials access tokens when oauth is set.
+// The TLS settings apply to the admin API and to the token endpoint alike.
+func newHTTPClient(ctx context.Context, oauth *OAuthConfig, tlsConfig *TLSConfig) (*http.Client, error) {
+	clientTLSConfig, err := tlsConfig.build()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []uhttp.Option{}
+	if clientTLSConfig != nil {
+		options = append(options, uhttp.WithTLSClientConfig(clientTLSConfig))
+	}
+
+	if oauth == nil {
+		options = append(options, uhttp.WithLogger(true, nil))
+		return uhttp.NewClient(ctx, options...)
+	}
+
+	if oauth.ClientID == "" || oauth.ClientSecret == "" || oauth.TokenURL == "" {
+		return nil, fmt.Errorf("client ID, client secret and token URL are required for OAuth authentication")
+	}
+	tokenURL, err := url.Parse(oauth.TokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token URL: %w", err)
+	}
+
+	credentials := uhttp.NewOAuth2ClientCredentials(
+		oauth.ClientID,
+		oauth.ClientSecret,
+		tokenURL,
+		oauth.Scopes,
+	)
+	return credentials.GetClient(ctx, options...)
+}
+
+// UsesOAuth reports whether requests are authenticated with bearer tokens instead of basic auth.
+func (c *PingFederateClient) UsesOAuth() bool {
+	return c.oauth != nil
+}
+
+// OAuthClientID returns the ID of the OAuth client the admin API tokens are obtained with, or an
+// empty string when basic authentication is used.
+func (c *PingFederateClient) OAuthClientID() string {
+	if c.oauth == nil {
+		return ""
+	}
+	return c.oauth.ClientID
+}
+
 // doRequest performs an HTTP request and handles common response processing.
 func (c *PingFederateClient) doRequest(ctx context.Context, method, path string, body interface{}, response interface{}) error {
-	// logger := ctxzap.Extract(ctx)
+	return c.doRequestWithQuery(ctx, method, path, nil, body, response)
+}
+
+// doRequestWithQuery performs an HTTP request with query parameters and handles common response processing.
+func (c *PingFederateClient) doRequestWithQuery(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body interface{},
+	response interface{},
+) error {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
 		return err
 	}
 	u = u.JoinPath(APIPath, path)
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
 
 	reqOpts := []uhttp.RequestOption{
 		uhttp.WithAcceptJSONHeader(),
@@ -70,7 +177,9 @@ func (c *PingFederateClient) doRequest(ctx context.Context, method, path string,
 	if err != nil {
 		return err
 	}
-	req.SetBasicAuth(c.Username, c.Password)
+	if c.oauth == nil {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
 
 	doOpts := []uhttp.DoOption{}
 	if response != nil {
@@ -84,108 +193,205 @@ func (c *PingFederateClient) doRequest(ctx context.Context, method, path string,
 	return err
 }
 
+// GetVersion retrieves the version of the PingFederate server.
+func (c *PingFederateClient) GetVersion(ctx context.Context) (*PingFederateVersion, error) {
+	var response PingFederateVersion
+	err := c.doRequest(ctx, http.MethodGet, "/version", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	return &response, nil
+}
+
 // GetUsers retrieves a list of PingFederate users from the API.
 func (c *PingFederateClient) GetUsers(ctx context.Context) ([]PingFederateUser, error) {
-	var response getAdminUsersResponse
-	err := c.doRequest(ctx, http.MethodGet, "/administrativeAccounts", nil, &response)
+	accounts, err := c.listAccounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
-	return response.Items, nil
+	return accounts.users, nil
 }
 
-// GetRoles retrieves a list of PingFederate roles from the API.
+// GetRoles returns the native PingFederate roles available on the server's release, followed by any
+// other role found on an administrative account, such as roles added by releases newer than the catalog.
 func (c *PingFederateClient) GetRoles(ctx context.Context) ([]PingFederateRole, error) {
-	var response getAdminUsersResponse
-	err := c.doRequest(ctx, http.MethodGet, "/administrativeAccounts", nil, &response)
+	version, err := c.GetVersion(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get roles: %w", err)
 	}
 
-	roles := make([]PingFederateRole, 0)
-	roleMap := make(map[string]bool)
+	accounts, err := c.listAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
 
-	for _, user := range response.Items {
-		for _, role := range user.Roles {
-			if !roleMap[role] {
-				roleMap[role] = true
-				roles = append(roles, PingFederateRole{
-					Name: role,
-					ID:   role,
-				})
+	roles := NativeRolesForVersion(version.Version)
+	for _, roleID := range accounts.roleIDs {
+		if slices.ContainsFunc(roles, func(role PingFederateRole) bool { return role.ID == roleID }) {
+			continue
+		}
+		// A role held on the server is synced even if the catalog says its release lacks it.
+		role, ok := nativeRole(roleID)
+		if !ok {
+			role = PingFederateRole{
+				Name: roleID,
+				ID:   roleID,
 			}
 		}
+		roles = append(roles, role)
 	}
 
-	// Adding the Auditor role.
-	roles = append(roles, PingFederateRole{
-		Name: AuditorRole,
-		ID:   AuditorRole,
-	})
-
 	return roles, nil
 }
 
 func (c *PingFederateClient) GetRoleAssignments(ctx context.Context, roleID string) ([]PingFederateUser, error) {
-	var response getAdminUsersResponse
-	err := c.doRequest(ctx, http.MethodGet, "/administrativeAccounts", nil, &response)
+	accounts, err := c.listAccounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get role assignments: %w", err)
 	}
 
-	var usersWithRole []PingFederateUser
-	if c.isAuditor(roleID) {
-		for _, user := range response.Items {
-			if user.IsAuditor {
-				usersWithRole = append(usersWithRole, user)
-			}
-		}
-	} else {
-		for _, user := range response.Items {
-			for _, role := range user.Roles {
-				if role == roleID {
-					usersWithRole = append(usersWithRole, user)
-					break
-				}
-			}
-		}
-	}
+	return accounts.roleUsers[roleID], nil
+}
 
-	return usersWithRole, nil
+// accountPath returns the path of an administrative account. Usernames may hold characters with a
+// meaning in paths, such as '/' or '%', so they are escaped.
+func accountPath(userID string) string {
+	return "/administrativeAccounts/" + url.PathEscape(userID)
 }
 
-func (c *PingFederateClient) AddUserToRole(ctx context.Context, userID string, roleID string) error {
-	var user PingFederateUser
-	err := c.doRequest(ctx, http.MethodGet, "/administrativeAccounts/"+userID, nil, &user)
-	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
-	}
+// getUserDocument fetches an administrative account bypassing every cache, returning both the raw
+// document and its decoded fields.
+func (c *PingFederateClient) getUserDocument(ctx context.Context, userID string) (accountDocument, *PingFederateUser, error) {
+	c.InvalidateAccounts(ctx)
 
-	if c.isAuditor(roleID) {
-		user.IsAuditor = true
-	} else {
-		user.Roles = append(user.Roles, roleID)
+	var document accountDocument
+	err := c.doRequest(ctx, http.MethodGet, accountPath(userID), nil, &document)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	err = c.doRequest(ctx, http.MethodPut, "/administrativeAccounts/"+userID, user, nil)
+	user, err := document.user()
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return nil, nil, fmt.Errorf("failed to decode user: %w", err)
 	}
+	return document, user, nil
+}
 
-	return nil
+// lockUser serializes read-modify-write cycles on one administrative account.
+func (c *PingFederateClient) lockUser(userID string) func() {
+	mtx, _ := c.userLocks.LoadOrStore(strings.ToLower(userID), &sync.Mutex{})
+	userMtx, _ := mtx.(*sync.Mutex)
+	userMtx.Lock()
+	return userMtx.Unlock
 }
 
-func (c *PingFederateClient) RemoveUserFromRole(ctx context.Context, userID string, roleID string) error {
-	var user PingFederateUser
-	err := c.doRequest(ctx, http.MethodGet, "/administrativeAccounts/"+userID, nil, &user)
-	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
+// updateUser fetches an administrative account, applies mutate to it and writes it back. Only the
+// roles, auditor and active fields changed by mutate are patched into the document sent back.
+// Updates to the same account are serialized, and the write is read back so that a change lost to
+// a concurrent writer outside this process is applied again on top of the newer document. Only the
+// change made by mutate is verified, other writers may change the account in the meantime.
+// It returns false without writing when mutate leaves the account unchanged.
+func (c *PingFederateClient) updateUser(ctx context.Context, userID string, mutate func(user *PingFederateUser) error) (bool, error) {
+	unlock := c.lockUser(userID)
+	defer unlock()
+
+	wrote := false
+	for attempt := 1; ; attempt++ {
+		document, before, err := c.getUserDocument(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		user, err := document.user()
+		if err != nil {
+			return false, fmt.Errorf("failed to decode user: %w", err)
+		}
+
+		err = mutate(user)
+		if err != nil {
+			return false, err
+		}
+		change := diffAccount(before, user)
+		if change.empty() {
+			// A lost write that another writer applied in the meantime still counts as ours.
+			return wrote, nil
+		}
+
+		err = document.patch(before, user)
+		if err != nil {
+			return false, fmt.Errorf("failed to encode user: %w", err)
+		}
+
+		err = c.doRequest(ctx, http.MethodPut, accountPath(userID), document, nil)
+		if err != nil {
+			c.InvalidateAccounts(ctx)
+			return false, fmt.Errorf("failed to update user: %w", err)
+		}
+		wrote = true
+
+		_, written, err := c.getUserDocument(ctx, userID)
+		if err != nil {
+			return false, fmt.Errorf("failed to verify user update: %w", err)
+		}
+		if change.appliedTo(written) {
+			return true, nil
+		}
+
+		if attempt == maxUpdateAttempts {
+			return false, fmt.Errorf("failed to update user %s: the change was overwritten by a concurrent update %d times", userID, attempt)
+		}
+		ctxzap.Extract(ctx).Warn(
+			"pingfederate-connector: account update was overwritten by a concurrent update, retrying",
+			zap.String("username", userID),
+			zap.Int("attempt", attempt),
+		)
 	}
+}
+
+// AddUserToRole grants roleID to the account after checking it against RoleRules. When
+// addPrerequisites is set, roles required by roleID are granted along with it. It returns
+// false when the account already holds the role.
+func (c *PingFederateClient) AddUserToRole(ctx context.Context, userID string, roleID string, addPrerequisites bool) (bool, error) {
+	return c.updateUser(ctx, userID, func(user *PingFederateUser) error {
+		roles, err := RolesToGrant(user.HeldRoles(), roleID, addPrerequisites)
+		if err != nil {
+			return fmt.Errorf("cannot grant %s to %s: %w", roleID, userID, err)
+		}
+		if len(roles) > 1 {
+			ctxzap.Extract(ctx).Info(
+				"pingfederate-connector: granting prerequisite roles",
+				zap.String("username", userID),
+				zap.String("role", roleID),
+				zap.Strings("roles", roles),
+			)
+		}
+
+		for _, role := range roles {
+			if c.isAuditor(role) {
+				user.IsAuditor = true
+			} else {
+				user.Roles = append(user.Roles, role)
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveUserFromRole revokes roleID from the account, refusing it while another role held by the account
+// requires roleID. It returns false when the account does not hold the role.
+func (c *PingFederateClient) RemoveUserFromRole(ctx context.Context, userID string, roleID string) (bool, error) {
+	return c.updateUser(ctx, userID, func(user *PingFederateUser) error {
+		if c.isAuditor(roleID) {
+			user.IsAuditor = false
+			return nil
+		}
+
+		err := CheckRevoke(user.HeldRoles(), roleID)
+		if err != nil {
+			return fmt.Errorf("cannot revoke %s from %s: %w", roleID, userID, err)
+		}
 
-	if c.isAuditor(roleID) {
-		user.IsAuditor = false
-	} else {
 		// Remove the role from the user's roles
 		newRoles := make([]string, 0)
 		for _, role := range user.Roles {
@@ -194,11 +400,50 @@ func (c *PingFederateClient) RemoveUserFromRole(ctx context.Context, userID stri
 			}
 		}
 		user.Roles = newRoles
+		return nil
+	})
+}
+
+// SetUserActive enables or disables an administrative account, leaving every other field untouched.
+// It returns false when the account is already in the requested state.
+func (c *PingFederateClient) SetUserActive(ctx context.Context, userID string, active bool) (bool, error) {
+	return c.updateUser(ctx, userID, func(user *PingFederateUser) error {
+		user.IsActive = active
+		return nil
+	})
+}
+
+// CreateUser creates a native administrative account with the given password and initial roles.
+func (c *PingFederateClient) CreateUser(ctx context.Context, user *PingFederateUser) (*PingFederateUser, error) {
+	var response PingFederateUser
+	err := c.doRequest(ctx, http.MethodPost, "/administrativeAccounts", user, &response)
+	c.InvalidateAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &response, nil
+}
+
+// DeleteUser deletes a native administrative account.
+func (c *PingFederateClient) DeleteUser(ctx context.Context, userID string) error {
+	err := c.doRequest(ctx, http.MethodDelete, accountPath(userID), nil, nil)
+	c.InvalidateAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
-	err = c.doRequest(ctx, http.MethodPut, "/administrativeAccounts/"+userID, user, nil)
+	return nil
+}
+
+// ResetPassword sets a new password on a native administrative account.
+func (c *PingFederateClient) ResetPassword(ctx context.Context, userID string, password string) error {
+	body := UserCredentials{
+		NewPassword: password,
+	}
+	err := c.doRequest(ctx, http.MethodPost, accountPath(userID)+"/resetPassword", body, nil)
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return fmt.Errorf("failed to reset password: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+)
+
+func accountRoles(t *testing.T, api *fakeapi.Server, username string) []string {
+	t.Helper()
+
+	var user PingFederateUser
+	err := json.Unmarshal(api.Document(accountPath(username)), &user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles := slices.Clone(user.Roles)
+	slices.Sort(roles)
+	return roles
+}
+
+func TestConcurrentGrantsAllTakeEffect(t *testing.T) {
+	ctx := context.Background()
+	api, c := newFakeAdminAPI(t, `{"username":"jdoe","active":true,"auditor":false,"roles":[]}`)
+
+	roles := []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole}
+	var wg sync.WaitGroup
+	errs := make([]error, len(roles))
+	for i, role := range roles {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = c.AddUserToRole(ctx, "jdoe", role, false)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("AddUserToRole(%s) error = %v", roles[i], err)
+		}
+	}
+	want := slices.Clone(roles)
+	slices.Sort(want)
+	if got := accountRoles(t, api, "jdoe"); !slices.Equal(got, want) {
+		t.Errorf("roles = %v, want %v", got, want)
+	}
+}
+
+func TestGrantIgnoresUnrelatedConcurrentChange(t *testing.T) {
+	ctx := context.Background()
+	api, c := newFakeAdminAPI(t, `{"username":"jdoe","active":true,"auditor":false,"roles":[]}`)
+
+	// An outside writer adds another role right after our write lands.
+	api.AfterWrite(func(r fakeapi.Request) {
+		api.AfterWrite(nil)
+		api.Set(r.Path, `{"username":"jdoe","active":true,"auditor":false,"roles":["ADMINISTRATOR","USER_ADMINISTRATOR"]}`)
+	})
+
+	granted, err := c.AddUserToRole(ctx, "jdoe", AdministratorRole, false)
+	if err != nil {
+		t.Fatalf("AddUserToRole() error = %v", err)
+	}
+	if !granted {
+		t.Error("AddUserToRole() reported the role as already granted")
+	}
+	if len(puts(api)) != 1 {
+		t.Errorf("got %d PUT requests, want 1", len(puts(api)))
+	}
+}
+
+func TestGrantRetriesLostUpdate(t *testing.T) {
+	ctx := context.Background()
+	original := `{"username":"jdoe","active":true,"auditor":false,"roles":[]}`
+	api, c := newFakeAdminAPI(t, original)
+
+	// An outside writer overwrites the account with its stale copy right after our first write.
+	api.AfterWrite(func(r fakeapi.Request) {
+		api.AfterWrite(nil)
+		api.Set(r.Path, `{"username":"jdoe","active":true,"auditor":false,"roles":["CRYPTO_ADMINISTRATOR"]}`)
+	})
+
+	granted, err := c.AddUserToRole(ctx, "jdoe", AdministratorRole, false)
+	if err != nil {
+		t.Fatalf("AddUserToRole() error = %v", err)
+	}
+	if !granted {
+		t.Error("AddUserToRole() reported the role as already granted")
+	}
+	if len(puts(api)) != 2 {
+		t.Errorf("got %d PUT requests, want 2", len(puts(api)))
+	}
+	want := []string{AdministratorRole, CryptoAdministratorRole}
+	if got := accountRoles(t, api, "jdoe"); !slices.Equal(got, want) {
+		t.Errorf("roles = %v, want %v", got, want)
+	}
+}
+
+func TestGrantFailsAfterRepeatedLostUpdates(t *testing.T) {
+	ctx := context.Background()
+	original := `{"username":"jdoe","active":true,"auditor":false,"roles":[]}`
+	api, c := newFakeAdminAPI(t, original)
+
+	api.AfterWrite(func(r fakeapi.Request) {
+		api.Set(r.Path, original)
+	})
+
+	_, err := c.AddUserToRole(ctx, "jdoe", AdministratorRole, false)
+	if err == nil {
+		t.Fatal("AddUserToRole() succeeded although every write was lost")
+	}
+	if len(puts(api)) != maxUpdateAttempts {
+		t.Errorf("got %d PUT requests, want %d", len(puts(api)), maxUpdateAttempts)
+	}
+}
+
+func TestAccountPathsEscapeUsernames(t *testing.T) {
+	ctx := context.Background()
+	const username = "ops/j%doe"
+	api, c := newFakeAdminAPI(t, `{"username":"ops/j%doe","active":true,"auditor":false,"roles":[]}`)
+
+	_, err := c.AddUserToRole(ctx, username, AdministratorRole, false)
+	if err != nil {
+		t.Fatalf("AddUserToRole() error = %v", err)
+	}
+	if got := accountRoles(t, api, username); !slices.Equal(got, []string{AdministratorRole}) {
+		t.Errorf("roles = %v, want %v", got, []string{AdministratorRole})
+	}
+
+	err = c.DeleteUser(ctx, username)
+	if err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if api.Document(accountPath(username)) != nil {
+		t.Error("DeleteUser() left the account in place")
+	}
+}
+
+func TestResetPasswordEscapesUsername(t *testing.T) {
+	api, c := newFakeAdminAPI(t, `{"username":"ops/j%doe","active":true,"auditor":false,"roles":[]}`)
+
+	err := c.ResetPassword(context.Background(), "ops/j%doe", "n3w-Passw0rd")
+	if err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	requests := api.Requests(http.MethodPost, accountPath("ops/j%doe")+"/resetPassword")
+	if len(requests) != 1 {
+		t.Errorf("got %d password resets of the account, want 1", len(requests))
+	}
+}
+
+func TestGetRolesFollowsServerVersion(t *testing.T) {
+	connectorAccount := `{"username":"connector","active":true,"auditor":false,"roles":["USER_ADMINISTRATOR"]}`
+
+	tests := []struct {
+		name     string
+		version  string
+		accounts []string
+		want     []string
+	}{
+		{
+			name:     "catalog of the release",
+			version:  "12.1.0.4",
+			accounts: []string{connectorAccount},
+			want:     []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole, ExpressionAdministratorRole, AuditorRole},
+		},
+		{
+			name:     "roles of later releases are left out",
+			version:  "7.0",
+			accounts: []string{connectorAccount},
+			want:     []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole},
+		},
+		{
+			name:     "held roles are kept",
+			version:  "7.0",
+			accounts: []string{`{"username":"jdoe","active":true,"auditor":false,"roles":["ADMINISTRATOR","EXPRESSION_ADMINISTRATOR","DATA_ADMINISTRATOR"]}`},
+			want:     []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole, ExpressionAdministratorRole, "DATA_ADMINISTRATOR"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, c := newFakeAdminAPI(t, tt.accounts...)
+			api.Set("/version", `{"version":"`+tt.version+`"}`)
+
+			roles, err := c.GetRoles(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := roleIDs(roles); !slices.Equal(got, tt.want) {
+				t.Errorf("GetRoles() = %v, want %v", got, tt.want)
+			}
+			for _, role := range roles {
+				if native, ok := nativeRole(role.ID); ok && role != native {
+					t.Errorf("role = %+v, want its catalog entry %+v", role, native)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// DefaultReplicationDelay is how long a replication waits for further changes to batch with.
+const DefaultReplicationDelay = 2 * time.Second
+
+// replicator batches "Replicate Configuration" requests. Every change restarts the delay, so changes
+// made in quick succession are replicated once, after the last of them.
+type replicator struct {
+	enabled bool
+	delay   time.Duration
+	mtx     sync.Mutex
+	timer   *time.Timer
+	// err is the failure of a replication not yet reported to a change.
+	err error
+	// scheduled tracks the replication waiting for its delay or running.
+	scheduled sync.WaitGroup
+	// running serializes replications.
+	running sync.Mutex
+}
+
+// ReplicateChanges schedules a push of the admin node configuration to the engine nodes of a
+// cluster, when replication is enabled. It does not wait for the replication: it returns the
+// failure of an earlier replication that has not been reported yet.
+func (c *PingFederateClient) ReplicateChanges(ctx context.Context) error {
+	if !c.replicator.enabled {
+		return nil
+	}
+
+	c.replicator.mtx.Lock()
+	defer c.replicator.mtx.Unlock()
+
+	err := c.replicator.err
+	c.replicator.err = nil
+
+	if c.replicator.timer != nil && c.replicator.timer.Stop() {
+		c.replicator.scheduled.Done()
+	}
+	// The replication outlives the request that scheduled it.
+	ctx = context.WithoutCancel(ctx)
+	c.replicator.scheduled.Add(1)
+	c.replicator.timer = time.AfterFunc(c.replicator.delay, func() {
+		defer c.replicator.scheduled.Done()
+		c.replicate(ctx)
+	})
+
+	return err
+}
+
+// FlushReplication replicates the changes still waiting for the replication delay right away, waits
+// for any running replication and returns the failure not yet reported to a change.
+func (c *PingFederateClient) FlushReplication(ctx context.Context) error {
+	if !c.replicator.enabled {
+		return nil
+	}
+
+	c.replicator.mtx.Lock()
+	pending := c.replicator.timer != nil && c.replicator.timer.Stop()
+	c.replicator.timer = nil
+	c.replicator.mtx.Unlock()
+
+	if pending {
+		c.replicate(ctx)
+		c.replicator.scheduled.Done()
+	}
+	c.replicator.scheduled.Wait()
+
+	c.replicator.mtx.Lock()
+	defer c.replicator.mtx.Unlock()
+	err := c.replicator.err
+	c.replicator.err = nil
+	return err
+}
+
+func (c *PingFederateClient) replicate(ctx context.Context) {
+	c.replicator.running.Lock()
+	defer c.replicator.running.Unlock()
+
+	err := c.doRequest(ctx, http.MethodPost, "/cluster/replicate", nil, nil)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("pingfederate-connector: cluster replication failed", zap.Error(err))
+
+		c.replicator.mtx.Lock()
+		c.replicator.err = fmt.Errorf("failed to replicate configuration: %w", err)
+		c.replicator.mtx.Unlock()
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+)
+
+const testReplicationDelay = 50 * time.Millisecond
+
+func newReplicatingClient(t *testing.T) (*fakeapi.Server, *PingFederateClient) {
+	t.Helper()
+
+	api := fakeapi.New(t)
+	c, err := New(context.Background(), api.URL, "connector", "password", nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.replicator.delay = testReplicationDelay
+	return api, c
+}
+
+// replications waits for the scheduled replication to run and returns the replications received.
+func replications(api *fakeapi.Server, c *PingFederateClient) int {
+	c.replicator.scheduled.Wait()
+	return len(api.Requests(http.MethodPost, "/cluster/replicate"))
+}
+
+func TestSequentialChangesReplicateOnce(t *testing.T) {
+	ctx := context.Background()
+	api, c := newReplicatingClient(t)
+
+	start := time.Now()
+	for range 5 {
+		err := c.ReplicateChanges(ctx)
+		if err != nil {
+			t.Fatalf("ReplicateChanges() error = %v", err)
+		}
+		time.Sleep(testReplicationDelay / 5)
+	}
+	if elapsed := time.Since(start); elapsed >= 2*testReplicationDelay {
+		t.Errorf("changes took %s, want them not to wait for the replication", elapsed)
+	}
+
+	if got := replications(api, c); got != 1 {
+		t.Errorf("got %d replications, want 1", got)
+	}
+
+	// A change after the batch was replicated needs a replication of its own.
+	err := c.ReplicateChanges(ctx)
+	if err != nil {
+		t.Fatalf("ReplicateChanges() error = %v", err)
+	}
+	if got := replications(api, c); got != 2 {
+		t.Errorf("got %d replications, want 2", got)
+	}
+}
+
+func TestConcurrentChangesReplicateOnce(t *testing.T) {
+	ctx := context.Background()
+	api, c := newReplicatingClient(t)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.ReplicateChanges(ctx)
+			if err != nil {
+				t.Errorf("ReplicateChanges() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := replications(api, c); got != 1 {
+		t.Errorf("got %d replications, want 1", got)
+	}
+}
+
+func TestFailedReplicationReportedOnNextChange(t *testing.T) {
+	ctx := context.Background()
+	api, c := newReplicatingClient(t)
+	api.Fail(http.MethodPost, "/cluster/replicate", http.StatusBadRequest)
+
+	err := c.ReplicateChanges(ctx)
+	if err != nil {
+		t.Fatalf("ReplicateChanges() error = %v", err)
+	}
+	replications(api, c)
+
+	err = c.ReplicateChanges(ctx)
+	if err == nil {
+		t.Error("ReplicateChanges() did not report the failed replication")
+	}
+	replications(api, c)
+
+	err = c.FlushReplication(ctx)
+	if err == nil {
+		t.Error("FlushReplication() did not report the second failed replication")
+	}
+	err = c.ReplicateChanges(ctx)
+	if err != nil {
+		t.Errorf("ReplicateChanges() reported a failure twice: %v", err)
+	}
+	replications(api, c)
+}
+
+func TestFlushReplicatesPendingChanges(t *testing.T) {
+	ctx := context.Background()
+	api, c := newReplicatingClient(t)
+	c.replicator.delay = time.Hour
+
+	err := c.ReplicateChanges(ctx)
+	if err != nil {
+		t.Fatalf("ReplicateChanges() error = %v", err)
+	}
+	err = c.FlushReplication(ctx)
+	if err != nil {
+		t.Fatalf("FlushReplication() error = %v", err)
+	}
+	if got := len(api.Requests(http.MethodPost, "/cluster/replicate")); got != 1 {
+		t.Errorf("got %d replications, want 1", got)
+	}
+}
+
+func TestReplicationDisabled(t *testing.T) {
+	api, c := newFakeAdminAPI(t)
+
+	err := c.ReplicateChanges(context.Background())
+	if err != nil {
+		t.Fatalf("ReplicateChanges() error = %v", err)
+	}
+	if got := replications(api, c); got != 0 {
+		t.Errorf("got %d replications, want none", got)
+	}
+}
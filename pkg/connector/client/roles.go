@@ -0,0 +1,234 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	CryptoAdministratorRole     = "CRYPTO_ADMINISTRATOR"
+	ExpressionAdministratorRole = "EXPRESSION_ADMINISTRATOR"
+)
+
+// NativeRoles is the catalog of administrative roles built into PingFederate. Every role the server
+// supports is synced whether or not an account currently holds it, so it can always be requested.
+// NativeRolesForVersion narrows the catalog to the roles of a given release.
+var NativeRoles = []PingFederateRole{
+	{
+		ID:          AdministratorRole,
+		Name:        "Admin",
+		Description: "Configure partner connections and most system settings, except local administrative accounts and local keys and certificates.",
+	},
+	{
+		ID:          UserAdministratorRole,
+		Name:        "User Admin",
+		Description: "Create, deactivate, reactivate and delete local administrative accounts, and reset their passwords.",
+	},
+	{
+		ID:          CryptoAdministratorRole,
+		Name:        "Crypto Admin",
+		Description: "Manage local keys and certificates.",
+	},
+	{
+		ID:          ExpressionAdministratorRole,
+		Name:        "Expression Admin",
+		Description: "Map user attributes with OGNL expressions. Requires the Admin role.",
+	},
+	{
+		ID:          AuditorRole,
+		Name:        "Auditor",
+		Description: "Read-only access to the administrative console and API. Cannot be combined with other roles.",
+	},
+}
+
+// nativeRole looks up a role in the catalog of native roles.
+func nativeRole(roleID string) (PingFederateRole, bool) {
+	for _, role := range NativeRoles {
+		if role.ID == roleID {
+			return role, true
+		}
+	}
+	return PingFederateRole{}, false
+}
+
+// roleIntroducedIn holds the PingFederate release that introduced each native role missing from
+// earlier releases. Roles without an entry are available on every release.
+var roleIntroducedIn = map[string]string{
+	CryptoAdministratorRole:     "6.0",
+	ExpressionAdministratorRole: "7.1",
+	AuditorRole:                 "8.2",
+}
+
+// NativeRolesForVersion returns the native roles available on the given PingFederate release, as
+// reported by /version. The whole catalog is returned when the version cannot be parsed.
+func NativeRolesForVersion(version string) []PingFederateRole {
+	roles := make([]PingFederateRole, 0, len(NativeRoles))
+	for _, role := range NativeRoles {
+		introducedIn, ok := roleIntroducedIn[role.ID]
+		if ok && compareVersions(version, introducedIn) < 0 {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+// compareVersions compares two dotted release numbers such as 12.1.0.4, returning -1, 0 or 1.
+// Missing components count as zero. A version that cannot be parsed compares as the newest release.
+func compareVersions(a, b string) int {
+	aParts, aOK := parseVersion(a)
+	bParts, bOK := parseVersion(b)
+	switch {
+	case !aOK && !bOK:
+		return 0
+	case !aOK:
+		return 1
+	case !bOK:
+		return -1
+	}
+
+	for len(aParts) < len(bParts) {
+		aParts = append(aParts, 0)
+	}
+	for len(bParts) < len(aParts) {
+		bParts = append(bParts, 0)
+	}
+	return slices.Compare(aParts, bParts)
+}
+
+func parseVersion(version string) ([]int, bool) {
+	// Pre-release builds carry a suffix such as 12.2.0-SNAPSHOT.
+	version, _, _ = strings.Cut(strings.TrimSpace(version), "-")
+	if version == "" {
+		return nil, false
+	}
+
+	fields := strings.Split(version, ".")
+	parts := make([]int, 0, len(fields))
+	for _, field := range fields {
+		part, err := strconv.Atoi(field)
+		if err != nil || part < 0 {
+			return nil, false
+		}
+		parts = append(parts, part)
+	}
+	return parts, true
+}
+
+// RoleRule describes the role combinations PingFederate accepts for an account holding a role.
+type RoleRule struct {
+	Requires      []string
+	ConflictsWith []string
+}
+
+// RoleRules lists the combination rules PingFederate enforces on administrative accounts.
+// An auditor cannot hold administrative roles, and expression administrators must also be administrators.
+var RoleRules = map[string]RoleRule{
+	AdministratorRole: {
+		ConflictsWith: []string{AuditorRole},
+	},
+	UserAdministratorRole: {
+		ConflictsWith: []string{AuditorRole},
+	},
+	CryptoAdministratorRole: {
+		ConflictsWith: []string{AuditorRole},
+	},
+	ExpressionAdministratorRole: {
+		Requires:      []string{AdministratorRole},
+		ConflictsWith: []string{AuditorRole},
+	},
+	AuditorRole: {
+		ConflictsWith: []string{
+			AdministratorRole,
+			UserAdministratorRole,
+			CryptoAdministratorRole,
+			ExpressionAdministratorRole,
+		},
+	},
+}
+
+// RolesToGrant returns the roles to add to an account currently holding held so that it ends up
+// with roleID, prerequisites first. A missing prerequisite is an error unless addPrerequisites is set.
+// A role the account already holds yields no roles to add.
+func RolesToGrant(held []string, roleID string, addPrerequisites bool) ([]string, error) {
+	holding := make(map[string]bool, len(held))
+	for _, role := range held {
+		holding[role] = true
+	}
+
+	toAdd := make([]string, 0)
+	var add func(role string) error
+	add = func(role string) error {
+		if holding[role] {
+			return nil
+		}
+
+		rule := RoleRules[role]
+		for _, conflict := range rule.ConflictsWith {
+			if holding[conflict] {
+				return fmt.Errorf("role %s cannot be combined with the %s role held by the account", role, conflict)
+			}
+		}
+
+		for _, prerequisite := range rule.Requires {
+			if holding[prerequisite] {
+				continue
+			}
+			if !addPrerequisites {
+				return fmt.Errorf("role %s requires the %s role, which the account does not hold", role, prerequisite)
+			}
+			if err := add(prerequisite); err != nil {
+				return fmt.Errorf("adding prerequisite of %s: %w", role, err)
+			}
+		}
+
+		holding[role] = true
+		toAdd = append(toAdd, role)
+		return nil
+	}
+
+	if err := add(roleID); err != nil {
+		return nil, err
+	}
+	return toAdd, nil
+}
+
+// CheckRevoke refuses to take roleID away from an account currently holding held while another held
+// role requires it, since PingFederate would reject the resulting combination.
+func CheckRevoke(held []string, roleID string) error {
+	for _, role := range held {
+		if role == roleID {
+			continue
+		}
+		if slices.Contains(RoleRules[role].Requires, roleID) {
+			return fmt.Errorf("role %s is required by the %s role held by the account, revoke %s first", roleID, role, role)
+		}
+	}
+	return nil
+}
+
+// ValidateRoles checks that a set of roles, such as the initial roles of a new account, satisfies RoleRules.
+func ValidateRoles(roles []string) error {
+	holding := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		holding[role] = true
+	}
+
+	for _, role := range roles {
+		rule := RoleRules[role]
+		for _, conflict := range rule.ConflictsWith {
+			if holding[conflict] {
+				return fmt.Errorf("role %s cannot be combined with the %s role", role, conflict)
+			}
+		}
+		for _, prerequisite := range rule.Requires {
+			if !holding[prerequisite] {
+				return fmt.Errorf("role %s requires the %s role", role, prerequisite)
+			}
+		}
+	}
+
+	return nil
+}
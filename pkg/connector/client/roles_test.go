@@ -0,0 +1,205 @@
+package client
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRolesToGrant(t *testing.T) {
+	tests := []struct {
+		name             string
+		held             []string
+		roleID           string
+		addPrerequisites bool
+		want             []string
+		wantErr          bool
+	}{
+		{
+			name:   "grants a role without rules",
+			held:   []string{},
+			roleID: CryptoAdministratorRole,
+			want:   []string{CryptoAdministratorRole},
+		},
+		{
+			name:   "already held role yields nothing",
+			held:   []string{AdministratorRole},
+			roleID: AdministratorRole,
+			want:   []string{},
+		},
+		{
+			name:    "auditor conflicts with administrator",
+			held:    []string{AuditorRole},
+			roleID:  AdministratorRole,
+			wantErr: true,
+		},
+		{
+			name:    "administrator conflicts with auditor",
+			held:    []string{AdministratorRole},
+			roleID:  AuditorRole,
+			wantErr: true,
+		},
+		{
+			name:    "missing prerequisite is refused",
+			held:    []string{UserAdministratorRole},
+			roleID:  ExpressionAdministratorRole,
+			wantErr: true,
+		},
+		{
+			name:   "held prerequisite is satisfied",
+			held:   []string{AdministratorRole},
+			roleID: ExpressionAdministratorRole,
+			want:   []string{ExpressionAdministratorRole},
+		},
+		{
+			name:             "prerequisite is added first",
+			held:             []string{UserAdministratorRole},
+			roleID:           ExpressionAdministratorRole,
+			addPrerequisites: true,
+			want:             []string{AdministratorRole, ExpressionAdministratorRole},
+		},
+		{
+			name:             "added prerequisite still conflicts with auditor",
+			held:             []string{AuditorRole},
+			roleID:           ExpressionAdministratorRole,
+			addPrerequisites: true,
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RolesToGrant(tt.held, tt.roleID, tt.addPrerequisites)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RolesToGrant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("RolesToGrant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRevoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		held    []string
+		roleID  string
+		wantErr bool
+	}{
+		{
+			name:   "role nothing depends on",
+			held:   []string{AdministratorRole, CryptoAdministratorRole},
+			roleID: CryptoAdministratorRole,
+		},
+		{
+			name:    "administrator required by expression administrator",
+			held:    []string{AdministratorRole, ExpressionAdministratorRole},
+			roleID:  AdministratorRole,
+			wantErr: true,
+		},
+		{
+			name:   "dependent role itself",
+			held:   []string{AdministratorRole, ExpressionAdministratorRole},
+			roleID: ExpressionAdministratorRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRevoke(tt.held, tt.roleID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckRevoke() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		roles   []string
+		wantErr bool
+	}{
+		{name: "no roles", roles: []string{}},
+		{name: "administrator and expression administrator", roles: []string{AdministratorRole, ExpressionAdministratorRole}},
+		{name: "expression administrator alone", roles: []string{ExpressionAdministratorRole}, wantErr: true},
+		{name: "auditor with user administrator", roles: []string{AuditorRole, UserAdministratorRole}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoles(tt.roles)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func roleIDs(roles []PingFederateRole) []string {
+	ids := make([]string, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	return ids
+}
+
+func TestNativeRolesForVersion(t *testing.T) {
+	allRoles := []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole, ExpressionAdministratorRole, AuditorRole}
+
+	tests := []struct {
+		name    string
+		version string
+		want    []string
+	}{
+		{
+			name:    "current release has every role",
+			version: "12.1.0.4",
+			want:    allRoles,
+		},
+		{
+			name:    "release introducing the auditor role",
+			version: "8.2",
+			want:    allRoles,
+		},
+		{
+			name:    "release before the auditor role",
+			version: "8.1.3",
+			want:    []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole, ExpressionAdministratorRole},
+		},
+		{
+			name:    "release before the expression administrator role",
+			version: "7.0.1",
+			want:    []string{AdministratorRole, UserAdministratorRole, CryptoAdministratorRole},
+		},
+		{
+			name:    "release before the crypto administrator role",
+			version: "5.3",
+			want:    []string{AdministratorRole, UserAdministratorRole},
+		},
+		{
+			name:    "pre-release suffix is ignored",
+			version: "8.2.0-SNAPSHOT",
+			want:    allRoles,
+		},
+		{
+			name:    "unparsable version has every role",
+			version: "unknown",
+			want:    allRoles,
+		},
+		{
+			name:    "empty version has every role",
+			version: "",
+			want:    allRoles,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roleIDs(NativeRolesForVersion(tt.version))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NativeRolesForVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
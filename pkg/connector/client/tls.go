@@ -0,0 +1,126 @@
+package client
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"slices"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// TLSConfig describes how the client verifies the admin node certificate and
+// how it authenticates itself when the admin API requires client certificates.
+type TLSConfig struct {
+	CABundlePath       string
+	ClientCertPath     string
+	ClientKeyPath      string
+	PKCS12Path         string
+	PKCS12Password     string
+	InsecureSkipVerify bool
+}
+
+// build converts the configuration into a tls.Config, returning nil when the defaults apply.
+func (t *TLSConfig) build() (*tls.Config, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	if t.CABundlePath == "" &&
+		t.ClientCertPath == "" &&
+		t.ClientKeyPath == "" &&
+		t.PKCS12Path == "" &&
+		!t.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only meant for lab instances with throwaway certificates.
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint:gosec // explicit opt-in through configuration
+	}
+
+	if t.CABundlePath != "" {
+		bundle, err := os.ReadFile(t.CABundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", t.CABundlePath)
+		}
+		config.RootCAs = pool
+	}
+
+	switch {
+	case t.PKCS12Path != "":
+		cert, err := loadPKCS12(t.PKCS12Path, t.PKCS12Password)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case t.ClientCertPath != "" || t.ClientKeyPath != "":
+		if t.ClientCertPath == "" || t.ClientKeyPath == "" {
+			return nil, fmt.Errorf("client certificate and client key are required together")
+		}
+		cert, err := tls.LoadX509KeyPair(t.ClientCertPath, t.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// loadPKCS12 reads a PKCS#12 archive holding the client certificate, its chain and private key.
+// Archives do not always store the client certificate first, so the leaf is the certificate
+// matching the private key and the rest of the archive is sent as its chain.
+func loadPKCS12(path string, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read PKCS#12 file: %w", err)
+	}
+
+	key, first, others, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to decode PKCS#12 file: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return tls.Certificate{}, fmt.Errorf("unsupported private key type %T in PKCS#12 file", key)
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return tls.Certificate{}, fmt.Errorf("unsupported public key type %T in PKCS#12 file", signer.Public())
+	}
+
+	certs := append([]*x509.Certificate{first}, others...)
+	leafIndex := slices.IndexFunc(certs, func(cert *x509.Certificate) bool {
+		return public.Equal(cert.PublicKey)
+	})
+	if leafIndex < 0 {
+		return tls.Certificate{}, fmt.Errorf("no certificate in PKCS#12 file matches its private key")
+	}
+
+	leaf := certs[leafIndex]
+	cert := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for i, chainCert := range certs {
+		if i != leafIndex {
+			cert.Certificate = append(cert.Certificate, chainCert.Raw)
+		}
+	}
+
+	return cert, nil
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent = template
+		parentKey = key
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestLoadPKCS12CAStoredBeforeLeaf(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "test ca", nil, nil)
+	leaf, leafKey := newTestCertificate(t, "connector", ca, caKey)
+
+	// The modern encoder uses PBES2 with AES, like OpenSSL 3, and the CA is stored first.
+	data, err := pkcs12.Modern.Encode(leafKey, ca, []*x509.Certificate{leaf}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "client.p12")
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := loadPKCS12(path, "secret")
+	if err != nil {
+		t.Fatalf("loadPKCS12() error = %v", err)
+	}
+	if !cert.Leaf.Equal(leaf) {
+		t.Errorf("leaf = %s, want %s", cert.Leaf.Subject, leaf.Subject)
+	}
+	if len(cert.Certificate) != 2 {
+		t.Fatalf("chain has %d certificates, want 2", len(cert.Certificate))
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(ca) {
+		t.Errorf("chain[1] = %s, want %s", parsed.Subject, ca.Subject)
+	}
+}
+
+func TestLoadPKCS12WrongPassword(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "test ca", nil, nil)
+	leaf, leafKey := newTestCertificate(t, "connector", ca, caKey)
+
+	data, err := pkcs12.Modern.Encode(leafKey, leaf, []*x509.Certificate{ca}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "client.p12")
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = loadPKCS12(path, "wrong")
+	if err == nil {
+		t.Fatal("loadPKCS12() with a wrong password succeeded")
+	}
+}
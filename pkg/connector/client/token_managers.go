@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// TokenLifetimeField is the configuration field of the token manager plugins holding the lifetime
+// of issued tokens, in minutes.
+const TokenLifetimeField = "Token Lifetime"
+
+// GetAccessTokenManagers retrieves the access token managers of the authorization server.
+func (c *PingFederateClient) GetAccessTokenManagers(ctx context.Context) ([]AccessTokenManager, error) {
+	var response getAccessTokenManagersResponse
+	err := c.doRequest(ctx, http.MethodGet, "/oauth/accessTokenManagers", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token managers: %w", err)
+	}
+	return response.Items, nil
+}
+
+// GetAccessTokenManager retrieves one access token manager.
+func (c *PingFederateClient) GetAccessTokenManager(ctx context.Context, id string) (*AccessTokenManager, error) {
+	var response AccessTokenManager
+	err := c.doRequest(ctx, http.MethodGet, "/oauth/accessTokenManagers/"+url.PathEscape(id), nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token manager: %w", err)
+	}
+	return &response, nil
+}
+
+// GetAccessTokenManagerSettings retrieves the server-wide access token manager settings, which name
+// the manager used by clients that do not pick one.
+func (c *PingFederateClient) GetAccessTokenManagerSettings(ctx context.Context) (*AccessTokenManagerSettings, error) {
+	var response AccessTokenManagerSettings
+	err := c.doRequest(ctx, http.MethodGet, "/oauth/accessTokenManagers/settings", nil, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token manager settings: %w", err)
+	}
+	return &response, nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import "testing"
+
+func TestPinnedToOtherAccessTokenManager(t *testing.T) {
+	tests := []struct {
+		name   string
+		client OAuthClient
+		want   bool
+	}{
+		{
+			name:   "not restricted",
+			client: OAuthClient{DefaultAccessTokenManagerRef: &ResourceLink{ID: "other"}},
+			want:   false,
+		},
+		{
+			name: "restricted to the manager",
+			client: OAuthClient{
+				DefaultAccessTokenManagerRef:        &ResourceLink{ID: "jwt"},
+				RestrictToDefaultAccessTokenManager: true,
+			},
+			want: false,
+		},
+		{
+			name: "restricted to another manager",
+			client: OAuthClient{
+				DefaultAccessTokenManagerRef:        &ResourceLink{ID: "other"},
+				RestrictToDefaultAccessTokenManager: true,
+			},
+			want: true,
+		},
+		{
+			name:   "restricted to the server default",
+			client: OAuthClient{RestrictToDefaultAccessTokenManager: true},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.client.PinnedToOtherAccessTokenManager("jwt", "reference")
+			if got != tt.want {
+				t.Errorf("PinnedToOtherAccessTokenManager() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
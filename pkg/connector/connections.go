@@ -0,0 +1,90 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	connectionActiveEntitlementName = "active"
+)
+
+// connectionEntitlements returns the active entitlement of a federation connection, grantable to
+// connections of resourceType. It is only granted to the connection itself, revoking it disables
+// the connection and granting it enables the connection again.
+func connectionEntitlements(connection *v2.Resource, resourceType *v2.ResourceType) []*v2.Entitlement {
+	return []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			connection,
+			connectionActiveEntitlementName,
+			entitlement.WithGrantableTo(resourceType),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Active", connection.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("The %s connection is active in PingFederate", connection.DisplayName),
+			),
+		),
+	}
+}
+
+// connectionGrants returns the active entitlement granted to the connection itself when the
+// connection is active.
+func connectionGrants(connection *v2.Resource) ([]*v2.Grant, error) {
+	appTrait, err := resource.GetAppTrait(connection)
+	if err != nil {
+		return nil, err
+	}
+	if !appTrait.GetProfile().GetFields()["active"].GetBoolValue() {
+		return nil, nil
+	}
+
+	return []*v2.Grant{
+		grant.NewGrant(connection, connectionActiveEntitlementName, connection.Id),
+	}, nil
+}
+
+// setConnectionActive enables or disables the federation connection the active entitlement
+// belongs to, annotating the grant or revoke when the connection is already in that state.
+func setConnectionActive(
+	ctx context.Context,
+	c *client.PingFederateClient,
+	connectionID *v2.ResourceId,
+	active bool,
+) (annotations.Annotations, error) {
+	var (
+		changed bool
+		err     error
+	)
+	switch connectionID.ResourceType {
+	case resourceTypeSpConnection.Id:
+		changed, err = c.SetSpConnectionActive(ctx, connectionID.Resource, active)
+	case resourceTypeIdpConnection.Id:
+		changed, err = c.SetIdpConnectionActive(ctx, connectionID.Resource, active)
+	default:
+		return nil, fmt.Errorf("pingfederate-connector: %s is not a federation connection", connectionID.ResourceType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !changed {
+		if active {
+			annos.Append(&v2.GrantAlreadyExists{})
+		} else {
+			annos.Append(&v2.GrantAlreadyRevoked{})
+		}
+		return annos, nil
+	}
+
+	replicateChanges(ctx, c, &annos)
+	return annos, nil
+}
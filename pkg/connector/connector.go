@@ -2,9 +2,16 @@ package connector
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/conductorone/baton-pingfed/pkg/connector/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -12,12 +19,18 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Connector struct {
-	ctx         context.Context
-	instanceUrl string
-	client      *client.PingFederateClient
+	ctx                  context.Context
+	instanceUrl          string
+	client               *client.PingFederateClient
+	guard                *accessGuard
+	addPrerequisiteRoles bool
+	secretOverlap        time.Duration
 }
 
 func fallBackToHTTPS(domain string) (string, error) {
@@ -37,8 +50,16 @@ func fallBackToHTTPS(domain string) (string, error) {
 // ResourceSyncers returns a ResourceSyncer for each resource type that should be synced from the upstream service.
 func (d *Connector) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
 	return []connectorbuilder.ResourceSyncer{
-		newUserBuilder(d.client),
-		newRoleBuilder(d.client),
+		newUserBuilder(d.client, d.guard),
+		newRoleBuilder(d.client, d.guard, d.addPrerequisiteRoles),
+		newOAuthClientBuilder(d.client, d.secretOverlap),
+		newScopeBuilder(d.client),
+		newAccessTokenManagerBuilder(d.client),
+		newSpConnectionBuilder(d.client),
+		newIdpConnectionBuilder(d.client),
+		newIdpAdapterBuilder(d.client),
+		newSpAdapterBuilder(d.client),
+		newAuthenticationPolicyBuilder(d.client),
 	}
 }
 
@@ -52,22 +73,163 @@ func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.R
 func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
 		DisplayName: "Ping Federate",
-		Description: "Connector syncing  PingFederate users",
+		Description: "Connector syncing PingFederate administrative accounts, roles, OAuth clients, scopes, access token managers, federation connections and adapters",
 	}, nil
 }
 
 // Validate is called to ensure that the connector is properly configured. It should exercise any API credentials
-// to be sure that they are valid.
+// to be sure that they are valid. It reports what the account may read and change in each area of the admin API:
+// administrative accounts need the User Admin role, the OAuth and federation configuration the Admin role.
 func (d *Connector) Validate(ctx context.Context) (annotations.Annotations, error) {
+	logger := ctxzap.Extract(ctx)
+
+	version, err := d.client.GetVersion(ctx)
+	if err != nil {
+		return nil, d.validationError(err)
+	}
+
+	users, err := d.client.GetUsers(ctx)
+	if err != nil {
+		return nil, d.validationError(err)
+	}
+
+	canReadConfiguration := true
+	_, err = d.client.GetAuthServerSettings(ctx)
+	if err != nil {
+		if status.Code(err) != codes.PermissionDenied {
+			return nil, d.validationError(err)
+		}
+		canReadConfiguration = false
+		logger.Warn(
+			"pingfederate-connector: account cannot read the OAuth and federation configuration, those resource types are skipped",
+			zap.String("requiredRole", client.AdministratorRole),
+		)
+	}
+
+	if d.client.UsesOAuth() {
+		logger.Info(
+			"PingFederate credentials validated, provisioning permissions depend on the roles mapped to the OAuth client",
+			zap.String("version", version.Version),
+			zap.Bool("canReadConfiguration", canReadConfiguration),
+		)
+		return nil, nil
+	}
+
+	canProvisionAccounts := false
+	canProvisionConfiguration := false
+	for _, user := range users {
+		if strings.EqualFold(user.Username, d.client.Username) {
+			canProvisionAccounts = !user.IsAuditor && slices.Contains(user.Roles, client.UserAdministratorRole)
+			canProvisionConfiguration = !user.IsAuditor && slices.Contains(user.Roles, client.AdministratorRole)
+			break
+		}
+	}
+
+	logger.Info(
+		"PingFederate credentials validated",
+		zap.String("version", version.Version),
+		zap.Bool("canProvisionAccounts", canProvisionAccounts),
+		zap.Bool("canReadConfiguration", canReadConfiguration),
+		zap.Bool("canProvisionConfiguration", canProvisionConfiguration),
+	)
+	if !canProvisionAccounts {
+		logger.Warn(
+			"pingfederate-connector: account can read administrative accounts but cannot grant or revoke roles",
+			zap.String("username", d.client.Username),
+			zap.String("requiredRole", client.UserAdministratorRole),
+		)
+	}
+	if canReadConfiguration && !canProvisionConfiguration {
+		logger.Warn(
+			"pingfederate-connector: account can read the OAuth and federation configuration but cannot grant scopes, "+
+				"rotate client secrets, enable or disable connections or replicate changes to the cluster",
+			zap.String("username", d.client.Username),
+			zap.String("requiredRole", client.AdministratorRole),
+		)
+	}
+
 	return nil, nil
 }
 
+// validationError turns a failed admin API call into an actionable error message.
+func (d *Connector) validationError(err error) error {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certInvalidErr x509.CertificateInvalidError
+	var certVerificationErr *tls.CertificateVerificationError
+	var recordHeaderErr tls.RecordHeaderError
+	var retrieveErr *oauth2.RetrieveError
+
+	switch {
+	case errors.As(err, &retrieveErr):
+		return fmt.Errorf("pingfederate-connector: failed to obtain an access token, check the OAuth client ID, secret, scopes and token URL: %w", err)
+	case errors.As(err, &unknownAuthorityErr),
+		errors.As(err, &hostnameErr),
+		errors.As(err, &certInvalidErr),
+		errors.As(err, &certVerificationErr):
+		return fmt.Errorf("pingfederate-connector: TLS verification of %s failed, check the certificate presented by the admin node or configure a CA bundle: %w", d.instanceUrl, err)
+	case errors.As(err, &recordHeaderErr):
+		return fmt.Errorf("pingfederate-connector: %s did not answer with TLS, check the scheme and admin port (usually 9999): %w", d.instanceUrl, err)
+	case errors.As(err, &dnsErr), errors.As(err, &opErr):
+		return fmt.Errorf("pingfederate-connector: unable to reach %s, check the instance URL and network access to the admin port: %w", d.instanceUrl, err)
+	}
+
+	// A web server or the runtime engine port answers with an HTML page instead of the admin API.
+	if strings.Contains(err.Error(), "unexpected content type") {
+		return fmt.Errorf("pingfederate-connector: %s%s did not return JSON, check the instance URL points at the admin API: %w", d.instanceUrl, client.APIPath, err)
+	}
+
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		if d.client.UsesOAuth() {
+			return fmt.Errorf("pingfederate-connector: %s rejected the access token, check that the admin API accepts OAuth tokens from this authorization server: %w", d.instanceUrl, err)
+		}
+		return fmt.Errorf("pingfederate-connector: invalid credentials for %s, check the username and password: %w", d.instanceUrl, err)
+	case codes.PermissionDenied:
+		if d.client.UsesOAuth() {
+			return fmt.Errorf(
+				"pingfederate-connector: OAuth client %s is not allowed to read administrative accounts, map its token to the %s or %s role in the admin API OAuth settings: %w",
+				d.client.OAuthClientID(),
+				client.UserAdministratorRole,
+				client.AuditorRole,
+				err,
+			)
+		}
+		return fmt.Errorf(
+			"pingfederate-connector: account %s is not allowed to read administrative accounts, it needs the %s or %s role: %w",
+			d.client.Username,
+			client.UserAdministratorRole,
+			client.AuditorRole,
+			err,
+		)
+	case codes.NotFound:
+		return fmt.Errorf("pingfederate-connector: admin API not found at %s%s, check the instance URL: %w", d.instanceUrl, client.APIPath, err)
+	default:
+		return fmt.Errorf("pingfederate-connector: failed to validate connection to %s: %w", d.instanceUrl, err)
+	}
+}
+
+// FlushReplication replicates the changes still waiting for the replication delay to the cluster and
+// returns the failure of a replication not yet reported to a change.
+func (d *Connector) FlushReplication(ctx context.Context) error {
+	return d.client.FlushReplication(ctx)
+}
+
 // New returns a new instance of the connector.
 func New(
 	ctx context.Context,
 	instanceURL string,
 	username string,
 	password string,
+	oauth *client.OAuthConfig,
+	tlsConfig *client.TLSConfig,
+	addPrerequisiteRoles bool,
+	lockoutProtection bool,
+	protectedUsernames []string,
+	replicateChanges bool,
+	secretOverlap time.Duration,
 ) (*Connector, error) {
 	logger := ctxzap.Extract(ctx)
 	instanceURL, err := fallBackToHTTPS(instanceURL)
@@ -80,6 +242,7 @@ func New(
 		zap.String("instanceURL", instanceURL),
 		zap.String("username", username),
 		zap.Bool("password?", password != ""),
+		zap.Bool("oauth?", oauth != nil),
 	)
 
 	PingFederateClient, err := client.New(
@@ -87,15 +250,21 @@ func New(
 		instanceURL,
 		username,
 		password,
+		oauth,
+		tlsConfig,
+		replicateChanges,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	connector := Connector{
-		client:      PingFederateClient,
-		ctx:         ctx,
-		instanceUrl: instanceURL,
+		client:               PingFederateClient,
+		ctx:                  ctx,
+		instanceUrl:          instanceURL,
+		guard:                newAccessGuard(PingFederateClient, lockoutProtection, protectedUsernames),
+		addPrerequisiteRoles: addPrerequisiteRoles,
+		secretOverlap:        secretOverlap,
 	}
 	return &connector, nil
 }
@@ -0,0 +1,106 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newValidatingConnector returns a connector whose account holds roles, and the warnings it logs.
+func newValidatingConnector(t *testing.T, roles ...string) (*Connector, *fakeapi.Server, *observer.ObservedLogs) {
+	t.Helper()
+
+	api, c, guard := newFakeAccounts(t, true, client.PingFederateUser{
+		Username: "connector",
+		IsActive: true,
+		Roles:    roles,
+	})
+	api.Set("/version", `{"version":"12.1.0.4"}`)
+	api.Set("/oauth/authServerSettings", `{"scopes":[],"exclusiveScopes":[]}`)
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	return &Connector{client: c, guard: guard, ctx: ctxzap.ToContext(context.Background(), zap.New(core))}, api, logs
+}
+
+func warnings(logs *observer.ObservedLogs) string {
+	messages := make([]string, 0)
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+func TestValidateReportsPermissionsPerArea(t *testing.T) {
+	tests := []struct {
+		name                string
+		roles               []string
+		configurationDenied bool
+		wantWarnings        []string
+	}{
+		{
+			name:  "admin and user admin",
+			roles: []string{client.AdministratorRole, client.UserAdministratorRole},
+		},
+		{
+			name:                "user admin only",
+			roles:               []string{client.UserAdministratorRole},
+			configurationDenied: true,
+			wantWarnings:        []string{"cannot read the OAuth and federation configuration"},
+		},
+		{
+			name:         "admin only",
+			roles:        []string{client.AdministratorRole},
+			wantWarnings: []string{"cannot grant or revoke roles"},
+		},
+		{
+			name:  "crypto admin only",
+			roles: []string{client.CryptoAdministratorRole},
+			wantWarnings: []string{
+				"cannot grant or revoke roles",
+				"cannot grant scopes",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, api, logs := newValidatingConnector(t, tt.roles...)
+			if tt.configurationDenied {
+				api.Fail(http.MethodGet, "/oauth/authServerSettings", http.StatusForbidden)
+			}
+
+			_, err := d.Validate(d.ctx)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			got := warnings(logs)
+			for _, want := range tt.wantWarnings {
+				if !strings.Contains(got, want) {
+					t.Errorf("warnings %q, want one containing %q", got, want)
+				}
+			}
+			if len(tt.wantWarnings) == 0 && got != "" {
+				t.Errorf("warnings %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestValidateFailsWithoutAccountAccess(t *testing.T) {
+	d, api, _ := newValidatingConnector(t, client.AdministratorRole)
+	api.Fail(http.MethodGet, "/administrativeAccounts", http.StatusForbidden)
+
+	_, err := d.Validate(d.ctx)
+	if err == nil || !strings.Contains(err.Error(), "not allowed to read administrative accounts") {
+		t.Errorf("Validate() error = %v, want the missing account access reported", err)
+	}
+}
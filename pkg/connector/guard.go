@@ -0,0 +1,166 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+)
+
+// accessGuard refuses changes that would lock the connector, or every administrator, out of
+// PingFederate, and changes to break-glass accounts that must never be modified.
+type accessGuard struct {
+	client             *client.PingFederateClient
+	enabled            bool
+	protectedUsernames []string
+	// adminMtx serializes the last administrator check with the write it allows, so concurrent
+	// removals cannot each see another administrator left and together remove them all.
+	adminMtx sync.Mutex
+}
+
+func newAccessGuard(client *client.PingFederateClient, enabled bool, protectedUsernames []string) *accessGuard {
+	return &accessGuard{
+		client:             client,
+		enabled:            enabled,
+		protectedUsernames: protectedUsernames,
+	}
+}
+
+// protectedAccounts lists the accounts the guard protects, for error messages.
+func (g *accessGuard) protectedAccounts() string {
+	accounts := make([]string, 0, len(g.protectedUsernames)+1)
+	if g.client.Username != "" {
+		accounts = append(accounts, g.client.Username+" (connector)")
+	}
+	for _, username := range g.protectedUsernames {
+		accounts = append(accounts, username+" (break-glass)")
+	}
+	if len(accounts) == 0 {
+		return "none configured"
+	}
+	return strings.Join(accounts, ", ")
+}
+
+func (g *accessGuard) isBreakGlass(username string) bool {
+	return slices.ContainsFunc(g.protectedUsernames, func(protected string) bool {
+		return strings.EqualFold(protected, username)
+	})
+}
+
+func (g *accessGuard) isConnectorAccount(username string) bool {
+	return g.client.Username != "" && strings.EqualFold(g.client.Username, username)
+}
+
+// checkModify refuses any change to a break-glass account.
+func (g *accessGuard) checkModify(action string, username string) error {
+	if g.isBreakGlass(username) {
+		return fmt.Errorf(
+			"pingfederate-connector: refusing to %s %s, it is a break-glass account that is never modified (protected accounts: %s)",
+			action,
+			username,
+			g.protectedAccounts(),
+		)
+	}
+	return nil
+}
+
+// checkRemoveAccess refuses taking access away from username: revoking roleID, or deactivating the
+// whole account when roleID is empty. Unless lockout protection is disabled, the connector's own
+// account and the last active administrator are protected. The caller must call release once
+// the change is written.
+func (g *accessGuard) checkRemoveAccess(ctx context.Context, action string, username string, roleID string) (func(), error) {
+	err := g.checkModify(action, username)
+	if err != nil {
+		return nil, err
+	}
+	if !g.enabled {
+		return func() {}, nil
+	}
+
+	err = g.checkConnectorAccount(action, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if roleID != "" && roleID != client.AdministratorRole {
+		return func() {}, nil
+	}
+	return g.checkLastAdministrator(ctx, action, username)
+}
+
+// checkDelete refuses deleting the connector's own account and the last active administrator,
+// whether or not lockout protection is enabled, along with break-glass accounts. The caller must
+// call release once the account is deleted.
+func (g *accessGuard) checkDelete(ctx context.Context, username string) (func(), error) {
+	err := g.checkModify("delete", username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.checkConnectorAccount("delete", username)
+	if err != nil {
+		return nil, err
+	}
+	return g.checkLastAdministrator(ctx, "delete", username)
+}
+
+// checkConnectorAccount refuses any change taking access away from the account the connector
+// authenticates as.
+func (g *accessGuard) checkConnectorAccount(action string, username string) error {
+	if g.isConnectorAccount(username) {
+		return fmt.Errorf(
+			"pingfederate-connector: refusing to %s %s, the connector authenticates as this account (protected accounts: %s)",
+			action,
+			username,
+			g.protectedAccounts(),
+		)
+	}
+	return nil
+}
+
+// checkLastAdministrator refuses any change taking access away from the last active account
+// holding ADMINISTRATOR. When the change is allowed, it holds the guard until release is called so
+// no other removal is checked before the change is written.
+func (g *accessGuard) checkLastAdministrator(ctx context.Context, action string, username string) (func(), error) {
+	g.adminMtx.Lock()
+
+	// Decide on fresh data, not on a listing cached earlier in the sync.
+	g.client.InvalidateAccounts(ctx)
+	users, err := g.client.GetUsers(ctx)
+	if err != nil {
+		g.adminMtx.Unlock()
+		return nil, err
+	}
+	if isLastAdministrator(users, username) {
+		g.adminMtx.Unlock()
+		return nil, fmt.Errorf(
+			"pingfederate-connector: refusing to %s %s, it is the last active account holding the %s role (protected accounts: %s)",
+			action,
+			username,
+			client.AdministratorRole,
+			g.protectedAccounts(),
+		)
+	}
+
+	return g.adminMtx.Unlock, nil
+}
+
+// isLastAdministrator reports whether username is the only active account holding ADMINISTRATOR.
+func isLastAdministrator(users []client.PingFederateUser, username string) bool {
+	holds := false
+	others := 0
+	for _, user := range users {
+		if !user.IsActive || !slices.Contains(user.Roles, client.AdministratorRole) {
+			continue
+		}
+		if strings.EqualFold(user.Username, username) {
+			holds = true
+		} else {
+			others++
+		}
+	}
+	return holds && others == 0
+}
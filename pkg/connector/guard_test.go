@@ -0,0 +1,136 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	"github.com/conductorone/baton-pingfed/pkg/connector/internal/fakeapi"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+func accountPath(username string) string {
+	return "/administrativeAccounts/" + url.PathEscape(username)
+}
+
+// newFakeAccounts starts a fake admin API holding the given accounts, along with a client of it
+// authenticating as the connector account and an access guard using that client.
+func newFakeAccounts(t *testing.T, lockoutProtection bool, accounts ...client.PingFederateUser) (*fakeapi.Server, *client.PingFederateClient, *accessGuard) {
+	t.Helper()
+
+	api := fakeapi.New(t)
+	for _, account := range accounts {
+		document, err := json.Marshal(account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		api.Set(accountPath(account.Username), string(document))
+	}
+
+	c, err := client.New(context.Background(), api.URL, "connector", "password", nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api, c, newAccessGuard(c, lockoutProtection, nil)
+}
+
+// administrators returns the active accounts holding the administrator role.
+func administrators(t *testing.T, api *fakeapi.Server, usernames ...string) []string {
+	t.Helper()
+
+	admins := make([]string, 0)
+	for _, username := range usernames {
+		document := api.Document(accountPath(username))
+		if document == nil {
+			continue
+		}
+		var account client.PingFederateUser
+		err := json.Unmarshal(document, &account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account.IsActive && slices.Contains(account.Roles, client.AdministratorRole) {
+			admins = append(admins, account.Username)
+		}
+	}
+	return admins
+}
+
+func administrator(username string) client.PingFederateUser {
+	return client.PingFederateUser{
+		Username: username,
+		IsActive: true,
+		Roles:    []string{client.AdministratorRole},
+	}
+}
+
+func roleGrant(username string, roleID string) *v2.Grant {
+	return &v2.Grant{
+		Entitlement: &v2.Entitlement{
+			Resource: &v2.Resource{
+				Id: &v2.ResourceId{ResourceType: resourceTypeRole.Id, Resource: roleID},
+			},
+		},
+		Principal: &v2.Resource{
+			Id: &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: username},
+		},
+	}
+}
+
+func TestConcurrentRevokesKeepAnAdministrator(t *testing.T) {
+	ctx := context.Background()
+	api, c, guard := newFakeAccounts(t, true, administrator("alice"), administrator("bob"))
+	// Both removals read the accounts before either of them writes, unless they are serialized.
+	api.SetListDelay(50 * time.Millisecond)
+	roles := newRoleBuilder(c, guard, false)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, username := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = roles.Revoke(ctx, roleGrant(username, client.AdministratorRole))
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("%d revokes failed, want exactly 1: %v", failed, errs)
+	}
+	if admins := administrators(t, api, "alice", "bob"); len(admins) != 1 {
+		t.Errorf("administrators = %v, want exactly one left", admins)
+	}
+}
+
+func TestDeleteRefusedWithoutLockoutProtection(t *testing.T) {
+	ctx := context.Background()
+	api, c, guard := newFakeAccounts(t, false, administrator("connector"), administrator("alice"))
+	users := newUserBuilder(c, guard)
+
+	_, err := users.Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: "Connector"})
+	if err == nil {
+		t.Error("deleting the connector account succeeded")
+	}
+
+	api.Set(accountPath("connector"), `{"username":"connector","active":true,"roles":[]}`)
+
+	_, err = users.Delete(ctx, &v2.ResourceId{ResourceType: resourceTypeUser.Id, Resource: "alice"})
+	if err == nil {
+		t.Error("deleting the last administrator succeeded")
+	}
+	if admins := administrators(t, api, "alice"); len(admins) != 1 {
+		t.Errorf("administrators = %v, want alice left", admins)
+	}
+}
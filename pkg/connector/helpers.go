@@ -0,0 +1,125 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// profileString reads a string value from an account profile.
+func profileString(profile map[string]interface{}, key string) string {
+	value, ok := profile[key]
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// profileStrings reads a list of strings from an account profile, accepting either a list
+// or a comma separated string.
+func profileStrings(profile map[string]interface{}, key string) []string {
+	rv := make([]string, 0)
+	switch value := profile[key].(type) {
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				rv = append(rv, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, item := range strings.Split(value, ",") {
+			if strings.TrimSpace(item) != "" {
+				rv = append(rv, strings.TrimSpace(item))
+			}
+		}
+	}
+	return rv
+}
+
+// replicateChanges schedules the replication of a change to the cluster engine nodes when replication
+// is enabled. Replications run in the background once changes stop coming in, so a failed earlier
+// replication is reported as a warning annotation on the next change, which itself succeeded.
+func replicateChanges(ctx context.Context, c *client.PingFederateClient, annos *annotations.Annotations) {
+	replicationErr := c.ReplicateChanges(ctx)
+	if replicationErr == nil {
+		return
+	}
+
+	ctxzap.Extract(ctx).Warn("pingfederate-connector: change applied but an earlier cluster replication failed", zap.Error(replicationErr))
+	warning, err := structpb.NewStruct(map[string]interface{}{
+		"warning": fmt.Sprintf("change applied but an earlier cluster replication failed: %s", replicationErr),
+	})
+	if err == nil {
+		annos.Append(warning)
+	}
+}
+
+// skipIfPermissionDenied returns nil, after logging a warning, when the admin API refused to read the
+// configuration behind resourceType. Configuration endpoints need the ADMINISTRATOR or AUDITOR role,
+// which an account limited to managing administrative accounts lacks, so the type is left out of the
+// sync rather than failing it. Any other error is returned unchanged.
+func skipIfPermissionDenied(ctx context.Context, resourceType *v2.ResourceType, err error) error {
+	if status.Code(err) != codes.PermissionDenied {
+		return err
+	}
+
+	ctxzap.Extract(ctx).Warn(
+		"pingfederate-connector: skipping resource type the account is not allowed to read",
+		zap.String("resourceType", resourceType.Id),
+		zap.String("requiredRole", client.AdministratorRole),
+		zap.Error(err),
+	)
+	return nil
+}
+
+// parsePageToken returns the page number held by a pagination token, starting at 1, and the page size.
+func parsePageToken(pToken *pagination.Token) (int, int, error) {
+	page := 1
+	pageSize := client.DefaultPageSize
+	if pToken == nil {
+		return page, pageSize, nil
+	}
+
+	if pToken.Size > 0 {
+		pageSize = pToken.Size
+	}
+	if pToken.Token != "" {
+		var err error
+		page, err = strconv.Atoi(pToken.Token)
+		if err != nil {
+			return 0, 0, fmt.Errorf("pingfederate-connector: invalid page token %q: %w", pToken.Token, err)
+		}
+	}
+	return page, pageSize, nil
+}
+
+// nextPageToken encodes the next page number, or returns an empty token when there are no more pages.
+func nextPageToken(nextPage int) string {
+	if nextPage == 0 {
+		return ""
+	}
+	return strconv.Itoa(nextPage)
+}
+
+// toInterfaceSlice converts a list of strings into a value a resource profile accepts.
+func toInterfaceSlice(values []string) []interface{} {
+	rv := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		rv = append(rv, value)
+	}
+	return rv
+}
@@ -0,0 +1,27 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestPermissionDeniedSkipsResourceType(t *testing.T) {
+	ctx := context.Background()
+	api, c, _ := newFakeAccounts(t, true)
+	api.Fail(http.MethodGet, "/oauth/clients", http.StatusForbidden)
+	api.Fail(http.MethodGet, "/idp/spConnections", http.StatusInternalServerError)
+
+	resources, _, _, err := newOAuthClientBuilder(c, 0).List(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("List() error = %v, want the denied resource type skipped", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("List() = %v, want no resources", resources)
+	}
+
+	_, _, _, err = newSpConnectionBuilder(c).List(ctx, nil, nil)
+	if err == nil {
+		t.Error("List() skipped a resource type the admin API failed to list")
+	}
+}
@@ -0,0 +1,191 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	adapterUsedEntitlementName = "used"
+)
+
+type idpAdapterBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// pluginTypes maps plugin descriptor IDs to the names of the plugins.
+func pluginTypes(descriptors []client.PluginDescriptor) map[string]string {
+	names := make(map[string]string, len(descriptors))
+	for _, descriptor := range descriptors {
+		names[descriptor.ID] = descriptor.Name
+	}
+	return names
+}
+
+// idpAdapterResource convert an IdpAdapter into a Resource. pluginType is the name of the plugin
+// the adapter is an instance of.
+func idpAdapterResource(adapter *client.IdpAdapter, pluginType string) (*v2.Resource, error) {
+	if pluginType == "" {
+		pluginType = adapter.PluginDescriptorRef.ID
+	}
+
+	profile := map[string]interface{}{
+		"id":                           adapter.ID,
+		"name":                         adapter.Name,
+		"pluginType":                   pluginType,
+		"pluginDescriptorId":           adapter.PluginDescriptorRef.ID,
+		"attributeContract":            toInterfaceSlice(adapter.AttributeContract.AttributeNames()),
+		"passwordCredentialValidators": toInterfaceSlice(adapter.Configuration.TableValues(client.PasswordCredentialValidatorField)),
+	}
+
+	newResource, err := resource.NewAppResource(
+		adapter.Name,
+		resourceTypeIdpAdapter,
+		adapter.ID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+		resource.WithDescription(pluginType),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *idpAdapterBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeIdpAdapter
+}
+
+func (o *idpAdapterBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	adapters, err := o.client.GetIdpAdapters(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeIdpAdapter, fmt.Errorf("failed to list idp adapters: %w", err))
+	}
+	descriptors, err := o.client.GetIdpAdapterDescriptors(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeIdpAdapter, fmt.Errorf("failed to list idp adapter descriptors: %w", err))
+	}
+	names := pluginTypes(descriptors)
+
+	rv := make([]*v2.Resource, 0, len(adapters))
+	for _, adapter := range adapters {
+		newResource, err := idpAdapterResource(&adapter, names[adapter.PluginDescriptorRef.ID])
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements returns the entitlement of the SP connections and authentication policies that
+// authenticate users with the adapter.
+func (o *idpAdapterBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			adapterUsedEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeSpConnection, resourceTypeAuthenticationPolicy),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("Uses %s", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Authenticates users with the %s IdP adapter", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants returns the authentication policies and the SP connections that reference the adapter.
+func (o *idpAdapterBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	adapterID := resource.Id.Resource
+
+	trees, err := o.client.GetAuthenticationPolicyTrees(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeIdpAdapter, fmt.Errorf("failed to list authentication policies: %w", err))
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, tree := range trees {
+		if !slices.Contains(tree.IdpAdapterIDs(), adapterID) {
+			continue
+		}
+		grants = append(grants, grant.NewGrant(
+			resource,
+			adapterUsedEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeAuthenticationPolicy.Id,
+				Resource:     tree.ID,
+			},
+		))
+	}
+
+	connections, err := o.client.ListSpConnections(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeIdpAdapter, fmt.Errorf("failed to list sp connections: %w", err))
+	}
+	for _, connection := range connections {
+		if !slices.Contains(connection.IdpAdapterIDs(), adapterID) {
+			continue
+		}
+		grants = append(grants, grant.NewGrant(
+			resource,
+			adapterUsedEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeSpConnection.Id,
+				Resource:     connection.ID,
+			},
+		))
+	}
+
+	return grants, "", nil, nil
+}
+
+func newIdpAdapterBuilder(client *client.PingFederateClient) *idpAdapterBuilder {
+	return &idpAdapterBuilder{
+		resourceType: resourceTypeIdpAdapter,
+		client:       client,
+	}
+}
@@ -0,0 +1,164 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type idpConnectionBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// idpConnectionResource convert an IdpConnection into a Resource. targetURLs are the target URLs
+// mapped to the connection.
+func idpConnectionResource(connection *client.IdpConnection, targetURLs []string) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"id":                            connection.ID,
+		"entityId":                      connection.EntityID,
+		"name":                          connection.Name,
+		"protocol":                      strings.Join(connection.Protocols(), ", "),
+		"active":                        connection.Active,
+		"baseUrl":                       connection.BaseURL,
+		"spAdapters":                    toInterfaceSlice(connection.SpAdapterIDs()),
+		"targetUrls":                    toInterfaceSlice(targetURLs),
+		"authenticationPolicyContracts": toInterfaceSlice(connection.AuthenticationPolicyContractIDs()),
+		"jitProvisioning":               false,
+	}
+	if jit := connection.JitProvisioning(); jit != nil {
+		profile["jitProvisioning"] = true
+		profile["jitUserRepository"] = jit.UserRepository.Type
+		profile["jitEventTrigger"] = jit.EventTrigger
+		profile["jitErrorHandling"] = jit.ErrorHandling
+	}
+
+	newResource, err := resource.NewAppResource(
+		connection.Name,
+		resourceTypeIdpConnection,
+		connection.ID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+		resource.WithDescription(connection.EntityID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *idpConnectionBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeIdpConnection
+}
+
+// List returns the IdP connections one page at a time.
+func (o *idpConnectionBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	page, pageSize, err := parsePageToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	connections, nextPage, err := o.client.GetIdpConnections(ctx, page, pageSize)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeIdpConnection, fmt.Errorf("failed to list idp connections: %w", err))
+	}
+
+	mappings, err := o.client.GetTargetURLMappings(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeIdpConnection, fmt.Errorf("failed to list target url mappings: %w", err))
+	}
+	targetURLs := make(map[string][]string)
+	for _, mapping := range mappings {
+		if mapping.Type != client.TargetURLMappingSpAdapter {
+			targetURLs[mapping.Ref.ID] = append(targetURLs[mapping.Ref.ID], mapping.URL)
+		}
+	}
+
+	rv := make([]*v2.Resource, 0, len(connections))
+	for _, connection := range connections {
+		newResource, err := idpConnectionResource(&connection, targetURLs[connection.ID])
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, nextPageToken(nextPage), nil, nil
+}
+
+// Entitlements returns the active entitlement of the connection.
+func (o *idpConnectionBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return connectionEntitlements(resource, resourceTypeIdpConnection), "", nil, nil
+}
+
+// Grants returns the active entitlement granted to the connection itself when it is active.
+func (o *idpConnectionBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	pToken *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	grants, err := connectionGrants(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return grants, "", nil, nil
+}
+
+// Grant enables a disabled connection.
+func (o *idpConnectionBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != resourceTypeIdpConnection.Id || principal.Id.Resource != entitlement.Resource.Id.Resource {
+		return nil, fmt.Errorf("pingfederate-connector: the active entitlement can only be granted to the connection itself")
+	}
+
+	return setConnectionActive(ctx, o.client, entitlement.Resource.Id, true)
+}
+
+// Revoke disables the connection, keeping its configuration so it can be enabled again.
+func (o *idpConnectionBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	return setConnectionActive(ctx, o.client, grant.Entitlement.Resource.Id, false)
+}
+
+func newIdpConnectionBuilder(client *client.PingFederateClient) *idpConnectionBuilder {
+	return &idpConnectionBuilder{
+		resourceType: resourceTypeIdpConnection,
+		client:       client,
+	}
+}
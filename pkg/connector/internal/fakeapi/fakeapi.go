@@ -0,0 +1,214 @@
+// Package fakeapi serves an in-memory PingFederate admin API for tests.
+package fakeapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const apiPath = "/pf-admin-api/v1"
+
+// Request is a request received by the server. Path is relative to the admin API and escaped.
+type Request struct {
+	Method string
+	Path   string
+	Body   json.RawMessage
+}
+
+// Server stores documents by path exactly as they were written. GET returns the document at a path
+// or, for the parent path of stored documents, a listing of them in the order they were first
+// stored, paged when the request asks for pages. PUT stores the body, DELETE removes the document
+// and any other POST answers with an empty document. Paths are escaped, so a document stored under
+// "/administrativeAccounts/a%2Fb" is only served for that escaped path.
+type Server struct {
+	URL string
+
+	mtx         sync.Mutex
+	documents   map[string]json.RawMessage
+	order       []string
+	collections map[string]bool
+	handlers    map[string]http.HandlerFunc
+	failures    map[string]int
+	requests    []Request
+	listDelay   time.Duration
+	afterWrite  func(r Request)
+}
+
+// New starts a server that is closed when the test ends.
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		documents:   make(map[string]json.RawMessage),
+		collections: make(map[string]bool),
+		handlers:    make(map[string]http.HandlerFunc),
+		failures:    make(map[string]int),
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	s.URL = server.URL
+	return s
+}
+
+// Set stores document at p as an outside writer would, and makes the parent of p listable.
+func (s *Server) Set(p string, document string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.set(p, json.RawMessage(document))
+}
+
+func (s *Server) set(p string, document json.RawMessage) {
+	if _, ok := s.documents[p]; !ok {
+		s.order = append(s.order, p)
+	}
+	s.documents[p] = document
+	s.collections[path.Dir(p)] = true
+}
+
+func (s *Server) remove(p string) {
+	delete(s.documents, p)
+	for i, stored := range s.order {
+		if stored == p {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Document returns the document stored at p, or nil.
+func (s *Server) Document(p string) json.RawMessage {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.documents[p]
+}
+
+// Handle serves method requests to p with handler instead of the stored documents.
+func (s *Server) Handle(method string, p string, handler http.HandlerFunc) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.handlers[method+" "+p] = handler
+}
+
+// Fail answers method requests to p with status, after recording them.
+func (s *Server) Fail(method string, p string, status int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.failures[method+" "+p] = status
+}
+
+// SetListDelay delays every listing, so concurrent readers all read before any of them writes.
+func (s *Server) SetListDelay(delay time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.listDelay = delay
+}
+
+// AfterWrite runs hook after each PUT or DELETE has been applied and before it is answered. The
+// hook may change documents and replace itself.
+func (s *Server) AfterWrite(hook func(r Request)) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.afterWrite = hook
+}
+
+// Requests returns the received requests with the given method, and path when it is not empty.
+func (s *Server) Requests(method string, p string) []Request {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	requests := make([]Request, 0)
+	for _, r := range s.requests {
+		if r.Method == method && (p == "" || r.Path == p) {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.EscapedPath(), apiPath)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request := Request{Method: r.Method, Path: p, Body: body}
+
+	s.mtx.Lock()
+	s.requests = append(s.requests, request)
+	handler := s.handlers[r.Method+" "+p]
+	status := s.failures[r.Method+" "+p]
+	listDelay := s.listDelay
+	s.mtx.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case handler != nil:
+		handler(w, r)
+	case status != 0:
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"resultId":"fake_failure","message":"failure injected by the test"}`))
+	case r.Method == http.MethodGet:
+		s.get(w, r, p, listDelay)
+	case r.Method == http.MethodPut:
+		s.write(request, func() { s.set(p, request.Body) })
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		s.write(request, func() { s.remove(p) })
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func (s *Server) write(request Request, apply func()) {
+	s.mtx.Lock()
+	apply()
+	hook := s.afterWrite
+	s.mtx.Unlock()
+
+	if hook != nil {
+		hook(request)
+	}
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, p string, listDelay time.Duration) {
+	s.mtx.Lock()
+	document, ok := s.documents[p]
+	listable := s.collections[p]
+	s.mtx.Unlock()
+
+	if ok {
+		_, _ = w.Write(document)
+		return
+	}
+	if !listable {
+		http.NotFound(w, r)
+		return
+	}
+
+	time.Sleep(listDelay)
+	s.mtx.Lock()
+	items := make([]json.RawMessage, 0)
+	for _, child := range s.order {
+		if path.Dir(child) == p {
+			items = append(items, s.documents[child])
+		}
+	}
+	s.mtx.Unlock()
+
+	if pageSize, err := strconv.Atoi(r.URL.Query().Get("numberPerPage")); err == nil && pageSize > 0 {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := min(max(page-1, 0)*pageSize, len(items))
+		items = items[start:min(start+pageSize, len(items))]
+	}
+	_ = json.NewEncoder(w).Encode(map[string][]json.RawMessage{"items": items})
+}
@@ -0,0 +1,169 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type oauthClientBuilder struct {
+	resourceType  *v2.ResourceType
+	client        *client.PingFederateClient
+	secretOverlap time.Duration
+}
+
+// oauthClientResource convert a OAuthClient into a Resource.
+func oauthClientResource(oauthClient *client.OAuthClient) (*v2.Resource, error) {
+	displayName := oauthClient.Name
+	if displayName == "" {
+		displayName = oauthClient.ClientID
+	}
+
+	profile := map[string]interface{}{
+		"clientId":       oauthClient.ClientID,
+		"name":           oauthClient.Name,
+		"description":    oauthClient.Description,
+		"enabled":        oauthClient.Enabled,
+		"grantTypes":     toInterfaceSlice(oauthClient.GrantTypes),
+		"redirectUris":   toInterfaceSlice(oauthClient.RedirectURIs),
+		"clientAuthType": oauthClient.ClientAuth.Type,
+		"creationDate":   oauthClient.CreationDate,
+		"restrictScopes": oauthClient.RestrictScopes,
+	}
+
+	newResource, err := resource.NewAppResource(
+		displayName,
+		resourceTypeOAuthClient,
+		oauthClient.ClientID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+		resource.WithDescription(oauthClient.Description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *oauthClientBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeOAuthClient
+}
+
+// List returns the OAuth clients one page at a time.
+func (o *oauthClientBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	page, pageSize, err := parsePageToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	oauthClients, nextPage, err := o.client.GetOAuthClients(ctx, page, pageSize)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeOAuthClient, fmt.Errorf("failed to list oauth clients: %w", err))
+	}
+
+	rv := make([]*v2.Resource, 0, len(oauthClients))
+	for _, oauthClient := range oauthClients {
+		newResource, err := oauthClientResource(&oauthClient)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, nextPageToken(nextPage), nil, nil
+}
+
+// Entitlements always returns an empty slice for OAuth clients.
+func (o *oauthClientBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return nil, "", nil, nil
+}
+
+// Grants always returns an empty slice for OAuth clients since they don't have any entitlements.
+func (o *oauthClientBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	pToken *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return nil, "", nil, nil
+}
+
+// RotateCapabilityDetails reports that client secrets are rotated to a random secret.
+func (o *oauthClientBuilder) RotateCapabilityDetails(
+	_ context.Context,
+) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return &v2.CredentialDetailsCredentialRotation{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+	}, nil, nil
+}
+
+// Rotate replaces the secret of an OAuth client with a new random secret. The previous secret stays
+// valid as a secondary secret for the configured overlap.
+func (o *oauthClientBuilder) Rotate(
+	ctx context.Context,
+	resourceID *v2.ResourceId,
+	credentialOptions *v2.CredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	secret, err := generatePassword(credentialOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = o.client.RotateOAuthClientSecret(ctx, resourceID.Resource, secret, o.secretOverlap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plaintexts := []*v2.PlaintextData{
+		{
+			Name:        "client_secret",
+			Description: "Secret of the PingFederate OAuth client",
+			Bytes:       []byte(secret),
+		},
+	}
+
+	var annos annotations.Annotations
+	replicateChanges(ctx, o.client, &annos)
+	return plaintexts, annos, nil
+}
+
+func newOAuthClientBuilder(client *client.PingFederateClient, secretOverlap time.Duration) *oauthClientBuilder {
+	return &oauthClientBuilder{
+		resourceType:  resourceTypeOAuthClient,
+		client:        client,
+		secretOverlap: secretOverlap,
+	}
+}
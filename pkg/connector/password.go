@@ -0,0 +1,74 @@
+package connector
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+const (
+	defaultPasswordLength = 20
+	minPasswordLength     = 12
+
+	passwordUpperCase = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordLowerCase = "abcdefghijkmnopqrstuvwxyz"
+	passwordDigits    = "23456789"
+	passwordSymbols   = "!#$%*+-.:=?@^_~"
+)
+
+// generatePassword returns a random password holding at least one character of every class, so it
+// satisfies PingFederate's password requirements for administrative accounts.
+func generatePassword(credentialOptions *v2.CredentialOptions) (string, error) {
+	randomPassword := credentialOptions.GetRandomPassword()
+	if randomPassword == nil {
+		return "", fmt.Errorf("pingfederate-connector: only random password credentials are supported")
+	}
+
+	length := int(randomPassword.GetLength())
+	if length == 0 {
+		length = defaultPasswordLength
+	}
+	if length < minPasswordLength {
+		return "", fmt.Errorf("pingfederate-connector: password length must be at least %d, got %d", minPasswordLength, length)
+	}
+
+	classes := []string{passwordUpperCase, passwordLowerCase, passwordDigits, passwordSymbols}
+	all := passwordUpperCase + passwordLowerCase + passwordDigits + passwordSymbols
+
+	password := make([]byte, 0, length)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so the guaranteed characters are not always at the start.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("pingfederate-connector: failed generating password: %w", err)
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, fmt.Errorf("pingfederate-connector: failed generating password: %w", err)
+	}
+	return charset[i.Int64()], nil
+}
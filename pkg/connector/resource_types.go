@@ -20,6 +20,68 @@ var (
 		Traits: []v2.ResourceType_Trait{
 			v2.ResourceType_TRAIT_USER,
 		},
+	}
+	// The OAuth client resource type is for the clients registered with the PingFederate authorization server.
+	resourceTypeOAuthClient = &v2.ResourceType{
+		Id:          "oauth_client",
+		DisplayName: "OAuth Client",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+		Annotations: annotations.New(&v2.SkipEntitlementsAndGrants{}),
+	}
+	// The scope resource type is for the common and exclusive scopes of the authorization server.
+	resourceTypeScope = &v2.ResourceType{
+		Id:          "scope",
+		DisplayName: "OAuth Scope",
+	}
+	// The access token manager resource type is for the token managers OAuth clients obtain access tokens from.
+	resourceTypeAccessTokenManager = &v2.ResourceType{
+		Id:          "access_token_manager",
+		DisplayName: "Access Token Manager",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	// The SP connection resource type is for the service providers PingFederate federates to as an IdP.
+	resourceTypeSpConnection = &v2.ResourceType{
+		Id:          "sp_connection",
+		DisplayName: "SP Connection",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	// The IdP connection resource type is for the partner identity providers PingFederate trusts as an SP.
+	resourceTypeIdpConnection = &v2.ResourceType{
+		Id:          "idp_connection",
+		DisplayName: "IdP Connection",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	// The IdP adapter resource type is for the adapters PingFederate authenticates users with as an IdP.
+	resourceTypeIdpAdapter = &v2.ResourceType{
+		Id:          "idp_adapter",
+		DisplayName: "IdP Adapter",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	// The SP adapter resource type is for the adapters PingFederate delivers federated users to as an SP.
+	resourceTypeSpAdapter = &v2.ResourceType{
+		Id:          "sp_adapter",
+		DisplayName: "SP Adapter",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
+	}
+	// The authentication policy resource type is for the trees of the default authentication policy.
+	resourceTypeAuthenticationPolicy = &v2.ResourceType{
+		Id:          "authentication_policy",
+		DisplayName: "Authentication Policy",
+		Traits: []v2.ResourceType_Trait{
+			v2.ResourceType_TRAIT_APP,
+		},
 		Annotations: annotations.New(&v2.SkipEntitlementsAndGrants{}),
 	}
 )
@@ -20,8 +20,10 @@ const (
 )
 
 type roleBuilder struct {
-	resourceType *v2.ResourceType
-	client       *client.PingFederateClient
+	resourceType         *v2.ResourceType
+	client               *client.PingFederateClient
+	guard                *accessGuard
+	addPrerequisiteRoles bool
 }
 
 // roleResource convert a PingFederateRole into a Resource.
@@ -30,7 +32,13 @@ func roleResource(ctx context.Context, role *client.PingFederateRole) (*v2.Resou
 		role.Name,
 		resourceTypeRole,
 		role.ID,
-		[]resource.RoleTraitOption{},
+		[]resource.RoleTraitOption{
+			resource.WithRoleProfile(map[string]interface{}{
+				"role_id":     role.ID,
+				"description": role.Description,
+			}),
+		},
+		resource.WithDescription(role.Description),
 	)
 	if err != nil {
 		return nil, err
@@ -157,29 +165,71 @@ func (o *roleBuilder) Grant(
 		return nil, fmt.Errorf("pingfederate-connector: only users can be granted roles")
 	}
 
-	err := o.client.AddUserToRole(
+	err := o.guard.checkModify("grant a role to", principal.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := o.client.AddUserToRole(
 		ctx,
 		principal.Id.Resource,
 		entitlement.Resource.Id.Resource,
+		o.addPrerequisiteRoles,
 	)
-	return nil, err
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !granted {
+		annos.Append(&v2.GrantAlreadyExists{})
+		return annos, nil
+	}
+
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
 }
 
+// Revoke removes the role from the account, unless the access guard refuses it.
 func (o *roleBuilder) Revoke(
 	ctx context.Context,
 	grant *v2.Grant,
 ) (annotations.Annotations, error) {
-	err := o.client.RemoveUserFromRole(
+	release, err := o.guard.checkRemoveAccess(
+		ctx,
+		fmt.Sprintf("revoke %s from", grant.Entitlement.Resource.Id.Resource),
+		grant.Principal.Id.Resource,
+		grant.Entitlement.Resource.Id.Resource,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := o.client.RemoveUserFromRole(
 		ctx,
 		grant.Principal.Id.Resource,
 		grant.Entitlement.Resource.Id.Resource,
 	)
-	return nil, err
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !revoked {
+		annos.Append(&v2.GrantAlreadyRevoked{})
+		return annos, nil
+	}
+
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
 }
 
-func newRoleBuilder(client *client.PingFederateClient) *roleBuilder {
+func newRoleBuilder(client *client.PingFederateClient, guard *accessGuard, addPrerequisiteRoles bool) *roleBuilder {
 	return &roleBuilder{
-		resourceType: resourceTypeRole,
-		client:       client,
+		resourceType:         resourceTypeRole,
+		client:               client,
+		guard:                guard,
+		addPrerequisiteRoles: addPrerequisiteRoles,
 	}
 }
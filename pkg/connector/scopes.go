@@ -0,0 +1,225 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	scopeEntitlementName = "allowed"
+)
+
+type scopeBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// scopeResource convert an OAuthScope into a Resource.
+func scopeResource(scope *client.OAuthScope, exclusive bool) (*v2.Resource, error) {
+	description := scope.Description
+	if exclusive {
+		description = fmt.Sprintf("Exclusive scope: %s", description)
+	}
+
+	newResource, err := resource.NewResource(
+		scope.Name,
+		resourceTypeScope,
+		scope.Name,
+		resource.WithDescription(description),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *scopeBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeScope
+}
+
+// List returns the common and exclusive scopes of the authorization server.
+func (o *scopeBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	settings, err := o.client.GetAuthServerSettings(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeScope, fmt.Errorf("failed to list scopes: %w", err))
+	}
+
+	rv := make([]*v2.Resource, 0, len(settings.Scopes)+len(settings.ExclusiveScopes))
+	for _, scope := range settings.Scopes {
+		newResource, err := scopeResource(&scope, false)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+	for _, scope := range settings.ExclusiveScopes {
+		newResource, err := scopeResource(&scope, true)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements returns the entitlement of OAuth clients allowed to request the scope.
+func (o *scopeBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			scopeEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeOAuthClient),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Scope", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Allowed to request the %s scope from PingFederate", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants returns the OAuth clients allowed to request the scope. Clients that do not restrict their
+// scopes may request every common scope, their grants are flagged with the unrestricted grant
+// metadata.
+func (o *scopeBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	settings, err := o.client.GetAuthServerSettings(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeScope, fmt.Errorf("failed to get scopes: %w", err))
+	}
+	scopeName := resource.Id.Resource
+	common := slices.ContainsFunc(settings.Scopes, func(scope client.OAuthScope) bool {
+		return scope.Name == scopeName
+	})
+
+	oauthClients, err := o.client.ListOAuthClients(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeScope, fmt.Errorf("failed to list oauth clients: %w", err))
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, oauthClient := range oauthClients {
+		principalID := &v2.ResourceId{
+			ResourceType: resourceTypeOAuthClient.Id,
+			Resource:     oauthClient.ClientID,
+		}
+
+		switch {
+		case slices.Contains(oauthClient.ExclusiveScopes, scopeName):
+			grants = append(grants, grant.NewGrant(resource, scopeEntitlementName, principalID))
+		case common && !oauthClient.RestrictScopes:
+			grants = append(grants, grant.NewGrant(
+				resource,
+				scopeEntitlementName,
+				principalID,
+				grant.WithGrantMetadata(map[string]interface{}{
+					"unrestricted": true,
+				}),
+			))
+		case common && slices.Contains(oauthClient.RestrictedScopes, scopeName):
+			grants = append(grants, grant.NewGrant(resource, scopeEntitlementName, principalID))
+		}
+	}
+
+	return grants, "", nil, nil
+}
+
+// Grant allows an OAuth client to request the scope.
+func (o *scopeBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != resourceTypeOAuthClient.Id {
+		return nil, fmt.Errorf("pingfederate-connector: only oauth clients can be granted scopes")
+	}
+
+	granted, err := o.client.AddScopeToOAuthClient(
+		ctx,
+		principal.Id.Resource,
+		entitlement.Resource.Id.Resource,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !granted {
+		annos.Append(&v2.GrantAlreadyExists{})
+		return annos, nil
+	}
+
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
+}
+
+// Revoke stops an OAuth client from requesting the scope.
+func (o *scopeBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	revoked, err := o.client.RemoveScopeFromOAuthClient(
+		ctx,
+		grant.Principal.Id.Resource,
+		grant.Entitlement.Resource.Id.Resource,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !revoked {
+		annos.Append(&v2.GrantAlreadyRevoked{})
+		return annos, nil
+	}
+
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
+}
+
+func newScopeBuilder(client *client.PingFederateClient) *scopeBuilder {
+	return &scopeBuilder{
+		resourceType: resourceTypeScope,
+		client:       client,
+	}
+}
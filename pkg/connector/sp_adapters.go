@@ -0,0 +1,168 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type spAdapterBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// spAdapterResource convert a SpAdapter into a Resource. pluginType is the name of the plugin the
+// adapter is an instance of, and targetURLs the target URLs mapped to the adapter.
+func spAdapterResource(adapter *client.SpAdapter, pluginType string, targetURLs []string) (*v2.Resource, error) {
+	if pluginType == "" {
+		pluginType = adapter.PluginDescriptorRef.ID
+	}
+
+	profile := map[string]interface{}{
+		"id":                 adapter.ID,
+		"name":               adapter.Name,
+		"pluginType":         pluginType,
+		"pluginDescriptorId": adapter.PluginDescriptorRef.ID,
+		"attributeContract":  toInterfaceSlice(adapter.AttributeContract.AttributeNames()),
+		"targetUrls":         toInterfaceSlice(targetURLs),
+	}
+
+	newResource, err := resource.NewAppResource(
+		adapter.Name,
+		resourceTypeSpAdapter,
+		adapter.ID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+		resource.WithDescription(pluginType),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *spAdapterBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeSpAdapter
+}
+
+func (o *spAdapterBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	adapters, err := o.client.GetSpAdapters(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeSpAdapter, fmt.Errorf("failed to list sp adapters: %w", err))
+	}
+	descriptors, err := o.client.GetSpAdapterDescriptors(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeSpAdapter, fmt.Errorf("failed to list sp adapter descriptors: %w", err))
+	}
+	names := pluginTypes(descriptors)
+
+	mappings, err := o.client.GetTargetURLMappings(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeSpAdapter, fmt.Errorf("failed to list target url mappings: %w", err))
+	}
+	targetURLs := make(map[string][]string)
+	for _, mapping := range mappings {
+		if mapping.Type == client.TargetURLMappingSpAdapter {
+			targetURLs[mapping.Ref.ID] = append(targetURLs[mapping.Ref.ID], mapping.URL)
+		}
+	}
+
+	rv := make([]*v2.Resource, 0, len(adapters))
+	for _, adapter := range adapters {
+		newResource, err := spAdapterResource(&adapter, names[adapter.PluginDescriptorRef.ID], targetURLs[adapter.ID])
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, "", nil, nil
+}
+
+// Entitlements returns the entitlement of the IdP connections that route users into the adapter.
+func (o *spAdapterBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	entitlements := []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			adapterUsedEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeIdpConnection),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("Uses %s", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("Routes federated users into the %s SP adapter", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, "", nil, nil
+}
+
+// Grants returns the IdP connections that route users into the adapter.
+func (o *spAdapterBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	connections, err := o.client.ListIdpConnections(ctx)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeSpAdapter, fmt.Errorf("failed to list idp connections: %w", err))
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, connection := range connections {
+		if !slices.Contains(connection.SpAdapterIDs(), resource.Id.Resource) {
+			continue
+		}
+		grants = append(grants, grant.NewGrant(
+			resource,
+			adapterUsedEntitlementName,
+			&v2.ResourceId{
+				ResourceType: resourceTypeIdpConnection.Id,
+				Resource:     connection.ID,
+			},
+		))
+	}
+
+	return grants, "", nil, nil
+}
+
+func newSpAdapterBuilder(client *client.PingFederateClient) *spAdapterBuilder {
+	return &spAdapterBuilder{
+		resourceType: resourceTypeSpAdapter,
+		client:       client,
+	}
+}
@@ -0,0 +1,148 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-pingfed/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+type spConnectionBuilder struct {
+	resourceType *v2.ResourceType
+	client       *client.PingFederateClient
+}
+
+// spConnectionResource convert a SpConnection into a Resource.
+func spConnectionResource(connection *client.SpConnection) (*v2.Resource, error) {
+	profile := map[string]interface{}{
+		"id":                 connection.ID,
+		"entityId":           connection.EntityID,
+		"name":               connection.Name,
+		"protocol":           strings.Join(connection.Protocols(), ", "),
+		"active":             connection.Active,
+		"baseUrl":            connection.BaseURL,
+		"signingKeyPairId":   connection.SigningKeyPairID(),
+		"contactCompany":     connection.ContactInfo.Company,
+		"contactEmail":       connection.ContactInfo.Email,
+		"contactFirstName":   connection.ContactInfo.FirstName,
+		"contactLastName":    connection.ContactInfo.LastName,
+		"contactPhoneNumber": connection.ContactInfo.Phone,
+	}
+
+	newResource, err := resource.NewAppResource(
+		connection.Name,
+		resourceTypeSpConnection,
+		connection.ID,
+		[]resource.AppTraitOption{
+			resource.WithAppProfile(profile),
+		},
+		resource.WithDescription(connection.EntityID),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newResource, nil
+}
+
+func (o *spConnectionBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return resourceTypeSpConnection
+}
+
+// List returns the SP connections one page at a time.
+func (o *spConnectionBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	page, pageSize, err := parsePageToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	connections, nextPage, err := o.client.GetSpConnections(ctx, page, pageSize)
+	if err != nil {
+		return nil, "", nil, skipIfPermissionDenied(ctx, resourceTypeSpConnection, fmt.Errorf("failed to list sp connections: %w", err))
+	}
+
+	rv := make([]*v2.Resource, 0, len(connections))
+	for _, connection := range connections {
+		newResource, err := spConnectionResource(&connection)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		rv = append(rv, newResource)
+	}
+
+	return rv, nextPageToken(nextPage), nil, nil
+}
+
+// Entitlements returns the active entitlement of the connection.
+func (o *spConnectionBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return connectionEntitlements(resource, resourceTypeSpConnection), "", nil, nil
+}
+
+// Grants returns the active entitlement granted to the connection itself when it is active.
+func (o *spConnectionBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	pToken *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	grants, err := connectionGrants(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return grants, "", nil, nil
+}
+
+// Grant enables a disabled connection.
+func (o *spConnectionBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != resourceTypeSpConnection.Id || principal.Id.Resource != entitlement.Resource.Id.Resource {
+		return nil, fmt.Errorf("pingfederate-connector: the active entitlement can only be granted to the connection itself")
+	}
+
+	return setConnectionActive(ctx, o.client, entitlement.Resource.Id, true)
+}
+
+// Revoke disables the connection, keeping its configuration so it can be enabled again.
+func (o *spConnectionBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	return setConnectionActive(ctx, o.client, grant.Entitlement.Resource.Id, false)
+}
+
+func newSpConnectionBuilder(client *client.PingFederateClient) *spConnectionBuilder {
+	return &spConnectionBuilder{
+		resourceType: resourceTypeSpConnection,
+		client:       client,
+	}
+}
@@ -3,17 +3,28 @@ package connector
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/conductorone/baton-pingfed/pkg/connector/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	"github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	userActiveEntitlementName = "active"
 )
 
 type userBuilder struct {
 	resourceType *v2.ResourceType
 	client       *client.PingFederateClient
+	guard        *accessGuard
 }
 
 // userResource convert a PingFederateUser into a Resource.
@@ -91,7 +102,8 @@ func (b *userBuilder) List(
 	return rv, "", nil, nil
 }
 
-// Entitlements always returns an empty slice for users.
+// Entitlements returns the active entitlement of an account. It is only granted to the account
+// itself, revoking it deactivates the account and granting it reactivates the account.
 func (o *userBuilder) Entitlements(
 	_ context.Context,
 	resource *v2.Resource,
@@ -102,13 +114,27 @@ func (o *userBuilder) Entitlements(
 	annotations.Annotations,
 	error,
 ) {
-	return nil, "", nil, nil
+	entitlements := []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			userActiveEntitlementName,
+			entitlement.WithGrantableTo(resourceTypeUser),
+			entitlement.WithDisplayName(
+				fmt.Sprintf("%s Active", resource.DisplayName),
+			),
+			entitlement.WithDescription(
+				fmt.Sprintf("The %s administrative account is active in PingFederate", resource.DisplayName),
+			),
+		),
+	}
+
+	return entitlements, "", nil, nil
 }
 
-// Grants always returns an empty slice for users since they don't have any entitlements.
+// Grants returns the active entitlement granted to the account itself when the account is enabled.
 func (o *userBuilder) Grants(
 	ctx context.Context,
-	resource *v2.Resource,
+	user *v2.Resource,
 	pToken *pagination.Token,
 ) (
 	[]*v2.Grant,
@@ -116,14 +142,265 @@ func (o *userBuilder) Grants(
 	annotations.Annotations,
 	error,
 ) {
-	return nil, "", nil, nil
+	userTrait, err := resource.GetUserTrait(user)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if userTrait.GetStatus().GetStatus() != v2.UserTrait_Status_STATUS_ENABLED {
+		return nil, "", nil, nil
+	}
+
+	grants := []*v2.Grant{
+		grant.NewGrant(user, userActiveEntitlementName, user.Id),
+	}
+	return grants, "", nil, nil
+}
+
+// Grant reactivates a deactivated administrative account.
+func (o *userBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	entitlement *v2.Entitlement,
+) (annotations.Annotations, error) {
+	if principal.Id.ResourceType != resourceTypeUser.Id || principal.Id.Resource != entitlement.Resource.Id.Resource {
+		return nil, fmt.Errorf("pingfederate-connector: the active entitlement can only be granted to the account itself")
+	}
+
+	err := o.guard.checkModify("reactivate", principal.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := o.client.SetUserActive(ctx, entitlement.Resource.Id.Resource, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !enabled {
+		annos.Append(&v2.GrantAlreadyExists{})
+		return annos, nil
+	}
+
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
+}
+
+// Revoke deactivates an administrative account, keeping it and its roles so it can be reactivated.
+// Unless the access guard is disabled, it refuses to deactivate the account the connector authenticates
+// as and the last active administrator.
+func (o *userBuilder) Revoke(
+	ctx context.Context,
+	grant *v2.Grant,
+) (annotations.Annotations, error) {
+	username := grant.Entitlement.Resource.Id.Resource
+	release, err := o.guard.checkRemoveAccess(ctx, "deactivate", username, "")
+	if err != nil {
+		return nil, err
+	}
+
+	disabled, err := o.client.SetUserActive(ctx, username, false)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	if !disabled {
+		annos.Append(&v2.GrantAlreadyRevoked{})
+		return annos, nil
+	}
+
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
+}
+
+// CreateAccountCapabilityDetails reports that accounts are created with a random password.
+func (o *userBuilder) CreateAccountCapabilityDetails(
+	_ context.Context,
+) (*v2.CredentialDetailsAccountProvisioning, annotations.Annotations, error) {
+	return &v2.CredentialDetailsAccountProvisioning{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+	}, nil, nil
+}
+
+// CreateAccount creates a native administrative account. The profile may carry the email, phoneNumber,
+// department and description of the account, and its initial roles as a list or a comma separated string.
+func (o *userBuilder) CreateAccount(
+	ctx context.Context,
+	accountInfo *v2.AccountInfo,
+	credentialOptions *v2.CredentialOptions,
+) (
+	connectorbuilder.CreateAccountResponse,
+	[]*v2.PlaintextData,
+	annotations.Annotations,
+	error,
+) {
+	profile := accountInfo.GetProfile().AsMap()
+
+	username := accountInfo.GetLogin()
+	if username == "" {
+		username = profileString(profile, "username")
+	}
+	if username == "" {
+		return nil, nil, nil, fmt.Errorf("pingfederate-connector: a login is required to create an account")
+	}
+
+	email := profileString(profile, "email")
+	if email == "" && len(accountInfo.GetEmails()) > 0 {
+		email = accountInfo.GetEmails()[0].GetAddress()
+	}
+
+	roles := profileStrings(profile, "roles")
+	for i, role := range roles {
+		roles[i] = strings.ToUpper(role)
+	}
+	err := client.ValidateRoles(roles)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pingfederate-connector: invalid initial roles: %w", err)
+	}
+
+	password, err := generatePassword(credentialOptions)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	user := &client.PingFederateUser{
+		Username:    username,
+		Password:    password,
+		Email:       email,
+		PhoneNumber: profileString(profile, "phoneNumber"),
+		Department:  profileString(profile, "department"),
+		Description: profileString(profile, "description"),
+		IsActive:    true,
+		Roles:       make([]string, 0, len(roles)),
+	}
+	for _, role := range roles {
+		if role == client.AuditorRole {
+			user.IsAuditor = true
+			continue
+		}
+		user.Roles = append(user.Roles, role)
+	}
+
+	created, err := o.client.CreateUser(ctx, user)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ur, err := userResource(*created)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	plaintexts := []*v2.PlaintextData{
+		{
+			Name:        "password",
+			Description: "Password of the PingFederate administrative account",
+			Bytes:       []byte(password),
+		},
+	}
+
+	var annos annotations.Annotations
+	replicateChanges(ctx, o.client, &annos)
+
+	return &v2.CreateAccountResponse_SuccessResult{
+		Resource:              ur,
+		IsCreateAccountResult: true,
+	}, plaintexts, annos, nil
+}
+
+// Create is not supported, accounts are created through account provisioning so they get a password.
+func (o *userBuilder) Create(
+	_ context.Context,
+	_ *v2.Resource,
+) (*v2.Resource, annotations.Annotations, error) {
+	return nil, nil, status.Error(codes.Unimplemented, "pingfederate-connector: use account provisioning to create administrative accounts")
+}
+
+// Delete removes a native administrative account. It always refuses to delete the account the
+// connector authenticates as and the last active account holding ADMINISTRATOR.
+func (o *userBuilder) Delete(
+	ctx context.Context,
+	resourceID *v2.ResourceId,
+) (annotations.Annotations, error) {
+	username := resourceID.Resource
+	release, err := o.guard.checkDelete(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.client.DeleteUser(ctx, username)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	var annos annotations.Annotations
+	replicateChanges(ctx, o.client, &annos)
+	return annos, nil
+}
+
+// RotateCapabilityDetails reports that credentials are rotated to a random password.
+func (o *userBuilder) RotateCapabilityDetails(
+	_ context.Context,
+) (*v2.CredentialDetailsCredentialRotation, annotations.Annotations, error) {
+	return &v2.CredentialDetailsCredentialRotation{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_RANDOM_PASSWORD,
+	}, nil, nil
+}
+
+// Rotate resets the password of a native administrative account to a new random password.
+func (o *userBuilder) Rotate(
+	ctx context.Context,
+	resourceID *v2.ResourceId,
+	credentialOptions *v2.CredentialOptions,
+) ([]*v2.PlaintextData, annotations.Annotations, error) {
+	username := resourceID.Resource
+	if o.guard.isConnectorAccount(username) {
+		return nil, nil, fmt.Errorf("pingfederate-connector: refusing to rotate the password of %s, the connector authenticates as this account", username)
+	}
+	err := o.guard.checkModify("rotate the password of", username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	password, err := generatePassword(credentialOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = o.client.ResetPassword(ctx, username, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plaintexts := []*v2.PlaintextData{
+		{
+			Name:        "password",
+			Description: "Password of the PingFederate administrative account",
+			Bytes:       []byte(password),
+		},
+	}
+
+	var annos annotations.Annotations
+	replicateChanges(ctx, o.client, &annos)
+	return plaintexts, annos, nil
 }
 
 func newUserBuilder(
 	client *client.PingFederateClient,
+	guard *accessGuard,
 ) *userBuilder {
 	return &userBuilder{
 		resourceType: resourceTypeUser,
 		client:       client,
+		guard:        guard,
 	}
 }